@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/NikolaB131/nmap-vulners-service/config"
+	"github.com/NikolaB131/nmap-vulners-service/internal/service"
+)
+
+const importCommand = "import"
+
+// runImport saves nmap XML files to the scans history without starting the server, e.g.
+// app import -c ./config.yml scan-1.xml scan-2.xml
+func runImport(args []string) {
+	flagSet := flag.NewFlagSet(importCommand, flag.ExitOnError)
+	var configPath string
+	flagSet.StringVar(&configPath, "c", "", "Path to yaml config file")
+	flagSet.StringVar(&configPath, "config", "", "Path to yaml config file (long version)")
+	flagSet.Usage = func() {
+		fmt.Fprintf(flagSet.Output(), "Usage: %s %s -c <config> <nmap xml file>...\n", os.Args[0], importCommand)
+		flagSet.PrintDefaults()
+	}
+	flagSet.Parse(args)
+	if configPath == "" {
+		panic("The --config argument is required")
+	}
+	if flagSet.NArg() == 0 {
+		panic("At least one nmap xml file is required")
+	}
+
+	config, err := config.NewConfig(configPath)
+	if err != nil {
+		panic(err)
+	}
+	if config.Storage.Path == "" {
+		panic("Storage path must be set in config to import scans")
+	}
+
+	logger := initLogger(config.Logger.Level)
+	scanRepository := mustInitScanRepository(config.Storage.Path, logger)
+
+	// Script path is not needed, nmap is not run during import
+	enrichers, _ := mustInitEnrichers(config, logger)
+	vulnersService := service.NewVulnersService(logger, service.NewNmapScanner("", "", ""), nil, enrichers, config.Vulners.CheckTimeout, config.Vulners.Concurrency, config.Vulners.StrictParsing)
+	scansService := service.NewScansService(logger, vulnersService, scanRepository, config.Scans.MaxRunning, config.Scans.Retention)
+
+	for _, path := range flagSet.Args() {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			panic(err)
+		}
+
+		scan, err := scansService.Import(context.Background(), data)
+		if err != nil {
+			panic(fmt.Errorf("%s: %w", path, err))
+		}
+		fmt.Printf("%s: imported as scan %s (%d hosts)\n", path, scan.ID, len(scan.Results))
+	}
+}
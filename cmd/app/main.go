@@ -1,24 +1,50 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"log/slog"
 	"net"
+	"net/netip"
 	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
 
 	"github.com/NikolaB131/nmap-vulners-service/config"
+	"github.com/NikolaB131/nmap-vulners-service/internal/auth"
 	grpccontroller "github.com/NikolaB131/nmap-vulners-service/internal/controller/grpc"
+	"github.com/NikolaB131/nmap-vulners-service/internal/enrich"
+	"github.com/NikolaB131/nmap-vulners-service/internal/lookup"
+	"github.com/NikolaB131/nmap-vulners-service/internal/ports"
+	"github.com/NikolaB131/nmap-vulners-service/internal/repository"
+	filerepository "github.com/NikolaB131/nmap-vulners-service/internal/repository/file"
+	memoryrepository "github.com/NikolaB131/nmap-vulners-service/internal/repository/memory"
+	"github.com/NikolaB131/nmap-vulners-service/internal/scheduler"
 	"github.com/NikolaB131/nmap-vulners-service/internal/service"
+	"github.com/NikolaB131/nmap-vulners-service/internal/target"
+	"github.com/NikolaB131/nmap-vulners-service/internal/tlsconfig"
+	"github.com/NikolaB131/nmap-vulners-service/internal/vulnersmock"
+	nmap_vulners_service "github.com/NikolaB131/nmap-vulners-service/pkg/proto"
+	"github.com/NikolaB131/nmap-vulners-service/pkg/sl"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
+const vulnerScriptArg = "vscript"
+
 type Flags struct {
 	ConfigPath       string
 	VulnerScriptPath string
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == importCommand {
+		runImport(os.Args[2:])
+		return
+	}
+
 	flags := mustParseFlags()
 
 	// Config
@@ -31,13 +57,79 @@ func main() {
 	logger := initLogger(config.Logger.Level)
 	logger.Info("Logger initialized", slog.String("level", config.Logger.Level))
 
+	// Repositories
+	scanRepository := mustInitScanRepository(config.Storage.Path, logger)
+
 	// Services
-	vulnersService := service.NewVulnersService(logger, config.Vulners.CheckTimeout, flags.VulnerScriptPath)
+	vulnersAPIURL := config.Vulners.APIURL
+	if config.Vulners.MockAPI.Enabled {
+		mockAPI, err := vulnersmock.New(logger, config.Vulners.MockAPI.FixturesDir, config.Vulners.APIKey)
+		if err != nil {
+			panic(err)
+		}
+		vulnersAPIURL, err = mockAPI.Start(config.Vulners.MockAPI.Listen)
+		if err != nil {
+			panic(err)
+		}
+		logger.Warn("Vulners mock API is enabled, vulnerabilities come from fixtures", slog.String("fixtures_dir", config.Vulners.MockAPI.FixturesDir))
+	}
+	var nmapScanner *service.NmapScanner
+	var resolver *lookup.Resolver
+	if config.Vulners.ScriptLookup() {
+		if flags.VulnerScriptPath == "" {
+			panic(fmt.Sprintf("The --%s argument is required for script lookup", vulnerScriptArg))
+		}
+		nmapScanner = service.NewNmapScanner(flags.VulnerScriptPath, vulnersAPIURL, config.Vulners.APIKey)
+	} else {
+		if flags.VulnerScriptPath != "" {
+			logger.Warn(fmt.Sprintf("The --%s argument is ignored, vulnerabilities are searched without nmap scripts in native lookup mode", vulnerScriptArg), slog.String("lookup", config.Vulners.Lookup))
+		}
+		nmapScanner = service.NewNmapScanner("", "", "")
+		resolver = lookup.NewResolver(logger, mustInitLookupSource(config, vulnersAPIURL, logger))
+	}
+	logger.Info("Vulnerabilities lookup mode", slog.String("lookup", config.Vulners.Lookup), slog.String("provider", config.Vulners.Provider))
+	enrichers, reloaders := mustInitEnrichers(config, logger)
+	authenticator, jwtVerifier := mustInitAuthenticator(config, logger)
+	if jwtVerifier != nil {
+		reloaders = append(reloaders, jwtVerifier)
+	}
+	go reloadOnSignal(logger, reloaders)
+	vulnersService := service.NewVulnersService(logger, nmapScanner, resolver, enrichers, config.Vulners.CheckTimeout, config.Vulners.Concurrency, config.Vulners.StrictParsing)
+	scansService := service.NewScansService(logger, vulnersService, scanRepository, config.Scans.MaxRunning, config.Scans.Retention)
+
+	portsParser, err := ports.NewParser(logger, config.Scans.NmapServicesPath, config.Scans.DefaultTcpPorts)
+	if err != nil {
+		panic(err)
+	}
+
+	// Scheduler
+	schedules := make([]scheduler.Schedule, len(config.Schedules))
+	for i, schedule := range config.Schedules {
+		schedules[i] = scheduler.Schedule{
+			Name:     schedule.Name,
+			Targets:  schedule.Targets,
+			TcpPorts: portsToStrings(schedule.TcpPorts),
+			UdpPorts: portsToStrings(schedule.UdpPorts),
+			Cron:     schedule.Cron,
+			Interval: schedule.Interval,
+		}
+		if len(schedule.TcpPorts) == 0 && len(schedule.UdpPorts) == 0 {
+			schedules[i].TcpPorts = portsParser.DefaultTCP()
+		}
+	}
+	targetsParser := target.NewParser(net.DefaultResolver, config.Scans.MaxHosts)
+	scopePolicy := mustInitScopePolicy(config, logger)
+	scansScheduler, err := scheduler.New(logger, scansService, targetsParser, scopePolicy, schedules)
+	if err != nil {
+		panic(err)
+	}
+	scansScheduler.Start()
+	defer scansScheduler.Stop()
 
 	// Server
-	gRPCServer := grpc.NewServer()
+	gRPCServer := grpc.NewServer(mustInitServerOptions(config, logger, authenticator)...)
 
-	grpccontroller.Register(gRPCServer, vulnersService)
+	grpccontroller.Register(gRPCServer, scansService, targetsParser, scopePolicy, portsParser)
 
 	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", config.GRPC.Port))
 	if err != nil {
@@ -45,29 +137,222 @@ func main() {
 	}
 	logger.Info("gRPC server started", slog.Int("port", config.GRPC.Port))
 
+	go stopOnSignal(logger, gRPCServer)
 	err = gRPCServer.Serve(listener)
 	if err != nil {
 		panic(err)
 	}
+	logger.Info("gRPC server stopped")
 }
 
 func mustParseFlags() Flags {
 	var configPath string
 	flag.StringVar(&configPath, "c", "", "Path to yaml config file")
 	flag.StringVar(&configPath, "config", "", "Path to yaml config file (long version)")
-	vulnerScriptArg := "vscript"
-	vulnerScriptPath := flag.String(vulnerScriptArg, "", "Path to vulnerability check .nse script")
+	vulnerScriptPath := flag.String(vulnerScriptArg, "", "Path to vulnerability check .nse script, required for script lookup")
 	flag.Parse()
 	if configPath == "" {
 		panic("The --config argument is required")
 	}
-	if *vulnerScriptPath == "" {
-		panic(fmt.Sprintf("The --%s argument is required", vulnerScriptArg))
-	}
 
 	return Flags{ConfigPath: configPath, VulnerScriptPath: *vulnerScriptPath}
 }
 
+func mustInitScanRepository(storagePath string, logger *slog.Logger) repository.ScanRepository {
+	if storagePath == "" {
+		logger.Warn("Storage path is not set, scans history is kept in memory only")
+		return memoryrepository.NewScanRepository()
+	}
+
+	scanRepository, err := filerepository.NewScanRepository(logger, storagePath)
+	if err != nil {
+		panic(err)
+	}
+	logger.Info("Scans history is stored on disk", slog.String("path", storagePath))
+	return scanRepository
+}
+
+func mustInitLookupSource(config *config.Config, vulnersAPIURL string, logger *slog.Logger) lookup.Source {
+	var source lookup.Source
+	if config.Vulners.NVDProvider() {
+		nvd, err := lookup.NewNVD(logger, config.NVD.FeedsDir)
+		if err != nil {
+			panic(err)
+		}
+		source = nvd
+	} else {
+		source = lookup.NewVulnersAPI(vulnersAPIURL, config.Vulners.APIKey, config.Vulners.APITimeout)
+	}
+
+	cacheConfig := config.Vulners.Cache
+	if cacheConfig.TTL == 0 {
+		logger.Warn("CPE cache is disabled")
+		return source
+	}
+	cache, err := lookup.NewCache(logger, source, cacheConfig.TTL, cacheConfig.MaxEntries, cacheConfig.Path)
+	if err != nil {
+		panic(err)
+	}
+	if cacheConfig.StatsInterval > 0 {
+		go cache.LogStats(context.Background(), cacheConfig.StatsInterval)
+	}
+	return cache
+}
+
+func mustInitScopePolicy(config *config.Config, logger *slog.Logger) *target.Policy {
+	global := target.Scope{Allow: mustParsePrefixes("scope", config.Scope.Allow), Deny: mustParsePrefixes("scope", config.Scope.Deny)}
+	clients := make([]target.ClientScope, len(config.Scope.Clients))
+	for i, clientScope := range config.Scope.Clients {
+		clients[i] = target.ClientScope{
+			Name:    clientScope.Name,
+			Clients: mustParsePrefixes("scope", clientScope.Addresses),
+			Scope:   target.Scope{Allow: mustParsePrefixes("scope", clientScope.Allow), Deny: mustParsePrefixes("scope", clientScope.Deny)},
+		}
+	}
+	if len(global.Allow) == 0 && len(global.Deny) == 0 && len(clients) == 0 {
+		logger.Warn("Scan scope is not limited, clients can scan any network")
+	}
+	return target.NewPolicy(global, clients)
+}
+
+// mustInitAuthenticator returns nil authenticator if authentication is disabled, and nil verifier if JWTs are not accepted.
+func mustInitAuthenticator(config *config.Config, logger *slog.Logger) (*auth.Authenticator, *auth.JWTVerifier) {
+	if !config.Auth.Enabled() {
+		logger.Warn("Authentication is disabled, any client can call any method")
+		return nil, nil
+	}
+
+	roles := make([]auth.Role, len(config.Auth.Roles))
+	for i, role := range config.Auth.Roles {
+		roles[i] = auth.Role{Name: role.Name, Methods: role.Methods}
+		if len(role.Allow) > 0 || len(role.Deny) > 0 {
+			section := fmt.Sprintf("auth role %q", role.Name)
+			roles[i].Scope = &target.Scope{Allow: mustParsePrefixes(section, role.Allow), Deny: mustParsePrefixes(section, role.Deny)}
+		}
+	}
+	keys := make([]auth.APIKey, len(config.Auth.APIKeys))
+	for i, key := range config.Auth.APIKeys {
+		keys[i] = auth.APIKey{Name: key.Name, Key: key.Key, Roles: key.Roles}
+	}
+
+	var jwtVerifier *auth.JWTVerifier
+	jwtConfig := config.Auth.JWT
+	if jwtConfig.JWKSPath != "" {
+		var err error
+		jwtVerifier, err = auth.NewJWTVerifier(logger, jwtConfig.JWKSPath, jwtConfig.Issuer, jwtConfig.Audience, jwtConfig.RolesClaim)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	authenticator, err := auth.NewAuthenticator(logger, &nmap_vulners_service.NetVulnService_ServiceDesc, roles, keys, jwtVerifier)
+	if err != nil {
+		panic(err)
+	}
+	logger.Info("Authentication is enabled", slog.Int("api_keys", len(keys)), slog.Bool("jwt", jwtVerifier != nil))
+	return authenticator, jwtVerifier
+}
+
+func mustParsePrefixes(section string, values []string) []netip.Prefix {
+	prefixes, err := target.ParsePrefixes(values)
+	if err != nil {
+		panic(fmt.Sprintf("%s: %s", section, err))
+	}
+	return prefixes
+}
+
+// mustInitServerOptions sets up TLS and, if authenticator is not nil, authentication of every call.
+func mustInitServerOptions(config *config.Config, logger *slog.Logger, authenticator *auth.Authenticator) []grpc.ServerOption {
+	var options []grpc.ServerOption
+	if authenticator != nil {
+		options = append(options, grpc.ChainUnaryInterceptor(authenticator.UnaryInterceptor()), grpc.ChainStreamInterceptor(authenticator.StreamInterceptor()))
+	}
+
+	tlsConfig := config.GRPC.TLS
+	if tlsConfig.CertFile == "" {
+		logger.Warn("gRPC TLS is disabled, connections are not encrypted")
+		return options
+	}
+
+	minVersion, err := tlsconfig.ParseVersion(tlsConfig.MinVersion)
+	if err != nil {
+		panic(err)
+	}
+	files := tlsconfig.Files{CertFile: tlsConfig.CertFile, KeyFile: tlsConfig.KeyFile, ClientCAFile: tlsConfig.ClientCAFile}
+	tlsReloader, err := tlsconfig.NewReloader(logger, files, minVersion)
+	if err != nil {
+		panic(err)
+	}
+	if tlsConfig.ReloadInterval > 0 {
+		go tlsReloader.Watch(context.Background(), tlsConfig.ReloadInterval)
+	}
+
+	return append(options, grpc.Creds(credentials.NewTLS(tlsReloader.ServerConfig())))
+}
+
+// reloader is a local catalog or JWKS which can be read from disk again without restarting the service
+type reloader interface {
+	Reload() error
+}
+
+func mustInitEnrichers(config *config.Config, logger *slog.Logger) ([]service.Enricher, []reloader) {
+	var enrichers []service.Enricher
+	var reloaders []reloader
+
+	if config.Enrichment.KEVPath != "" {
+		kev, err := enrich.NewKEV(logger, config.Enrichment.KEVPath)
+		if err != nil {
+			panic(err)
+		}
+		enrichers = append(enrichers, kev)
+		reloaders = append(reloaders, kev)
+	}
+
+	if config.Enrichment.EPSSPath != "" {
+		epss, err := enrich.NewEPSS(logger, config.Enrichment.EPSSPath)
+		if err != nil {
+			panic(err)
+		}
+		enrichers = append(enrichers, epss)
+		reloaders = append(reloaders, epss)
+	}
+
+	return enrichers, reloaders
+}
+
+// reloadOnSignal reloads enrichment catalogs and JWKS on SIGHUP, e.g. after a cron job has downloaded their new versions.
+func reloadOnSignal(logger *slog.Logger, reloaders []reloader) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	for range signals {
+		logger.Info("SIGHUP received, reloading enrichment catalogs and JWKS")
+		for _, r := range reloaders {
+			err := r.Reload()
+			if err != nil {
+				logger.Error("unable to reload, previous version is kept", sl.Err(err))
+			}
+		}
+	}
+}
+
+// stopOnSignal stops the server on SIGTERM or SIGINT after running calls finish, so main returns
+// and stops the scheduler, cancelling scheduled scans in progress.
+func stopOnSignal(logger *slog.Logger, server *grpc.Server) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, os.Interrupt)
+	received := <-signals
+	logger.Info("Shutting down", slog.String("signal", received.String()))
+	server.GracefulStop()
+}
+
+func portsToStrings(ports []int) []string {
+	converted := make([]string, len(ports))
+	for i, port := range ports {
+		converted[i] = strconv.Itoa(port)
+	}
+	return converted
+}
+
 func initLogger(logLevel string) *slog.Logger {
 	level := slog.LevelDebug
 
@@ -4,20 +4,50 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"gopkg.in/yaml.v3"
 )
 
+// Vulnerabilities lookup modes
+const (
+	LookupNative = "native" // nmap detects services only, vulnerabilities are searched by the service itself
+	LookupScript = "script" // nmap runs vulners.nse which searches vulnerabilities
+)
+
+// Vulnerabilities providers for native lookup
+const (
+	ProviderVulners = "vulners" // vulners API
+	ProviderNVD     = "nvd"     // NVD JSON feeds from disk
+)
+
 type (
 	Config struct {
-		GRPC    `yaml:"grpc"`
-		Logger  `yaml:"logger"`
-		Vulners `yaml:"vulners"`
+		GRPC       `yaml:"grpc"`
+		Logger     `yaml:"logger"`
+		Vulners    `yaml:"vulners"`
+		Scans      `yaml:"scans"`
+		Storage    `yaml:"storage"`
+		NVD        `yaml:"nvd"`
+		Enrichment `yaml:"enrichment"`
+		Scope      `yaml:"scope"`
+		Auth       `yaml:"auth"`
+		Schedules  []Schedule `yaml:"schedules"`
 	}
 
 	GRPC struct {
 		Port int `yaml:"port"`
+		TLS  TLS `yaml:"tls"`
+	}
+
+	// TLS of the gRPC server, empty cert_file means plaintext connections. Files are reloaded when they change.
+	TLS struct {
+		CertFile       string        `yaml:"cert_file"`
+		KeyFile        string        `yaml:"key_file"`
+		ClientCAFile   string        `yaml:"client_ca_file"`  // enables mutual TLS, clients must present a certificate signed by it
+		MinVersion     string        `yaml:"min_version"`     // possible values: 1.2, 1.3
+		ReloadInterval time.Duration `yaml:"reload_interval"` // how often files are checked for changes, zero disables reloading
 	}
 
 	Logger struct {
@@ -25,10 +55,124 @@ type (
 	}
 
 	Vulners struct {
-		CheckTimeout time.Duration `yaml:"check_timeout"`
+		CheckTimeout  time.Duration `yaml:"check_timeout"`
+		Concurrency   int           `yaml:"concurrency"`
+		StrictParsing bool          `yaml:"strict_parsing"`
+		Lookup        string        `yaml:"lookup"`
+		Provider      string        `yaml:"provider"`
+		APIURL        string        `yaml:"api_url"` // empty means vulners.com
+		APIKey        string        `yaml:"api_key"`
+		APITimeout    time.Duration `yaml:"api_timeout"`
+		MockAPI       MockAPI       `yaml:"mock_api"`
+		Cache         Cache         `yaml:"cache"`
+	}
+
+	// Cache keeps native lookup results by CPE, zero TTL disables it.
+	Cache struct {
+		TTL           time.Duration `yaml:"ttl"`
+		MaxEntries    int           `yaml:"max_entries"`
+		Path          string        `yaml:"path"`           // directory to keep entries between restarts, empty means in memory only
+		StatsInterval time.Duration `yaml:"stats_interval"` // how often hits and misses are logged, 0 disables the summary
+	}
+
+	// MockAPI is an in-process stand-in for vulners API, when enabled it replaces api_url.
+	MockAPI struct {
+		Enabled     bool   `yaml:"enabled"`
+		Listen      string `yaml:"listen"`
+		FixturesDir string `yaml:"fixtures_dir"`
+	}
+
+	Scans struct {
+		MaxRunning       int           `yaml:"max_running"`
+		Retention        time.Duration `yaml:"retention"`
+		MaxHosts         int           `yaml:"max_hosts"`
+		DefaultTcpPorts  []string      `yaml:"default_tcp_ports"`  // used when a request has neither TCP nor UDP ports, empty means nmap defaults
+		NmapServicesPath string        `yaml:"nmap_services_path"` // ports frequencies for top-N presets
+	}
+
+	Storage struct {
+		Path string `yaml:"path"` // directory for scans history, empty means in memory only
+	}
+
+	NVD struct {
+		FeedsDir string `yaml:"feeds_dir"` // directory with NVD CVE JSON 2.0 feeds (*.json or *.json.gz)
+	}
+
+	// Enrichment catalogs are reloaded from disk on SIGHUP, empty path disables the enrichment.
+	Enrichment struct {
+		KEVPath  string `yaml:"kev_path"`  // CISA Known Exploited Vulnerabilities catalog JSON
+		EPSSPath string `yaml:"epss_path"` // EPSS scores CSV snapshot, plain or gzipped
+	}
+
+	// Scope limits networks clients can scan, CIDR blocks or single addresses are listed.
+	Scope struct {
+		Allow   []string      `yaml:"allow"` // empty means any network is allowed
+		Deny    []string      `yaml:"deny"`  // takes precedence over allow, also for clients
+		Clients []ClientScope `yaml:"clients"`
+	}
+
+	// ClientScope replaces the global allow list for clients connecting from Addresses.
+	ClientScope struct {
+		Name      string   `yaml:"name"`
+		Addresses []string `yaml:"addresses"` // client addresses
+		Allow     []string `yaml:"allow"`
+		Deny      []string `yaml:"deny"`
+	}
+
+	// Auth of gRPC calls by bearer tokens, disabled if neither api_keys nor jwt.jwks_path is set.
+	Auth struct {
+		Roles   []Role   `yaml:"roles"`
+		APIKeys []APIKey `yaml:"api_keys"`
+		JWT     JWT      `yaml:"jwt"`
+	}
+
+	// Role allows calling Methods and scanning networks of Allow except Deny, in addition to the scope limits.
+	Role struct {
+		Name    string   `yaml:"name"`
+		Methods []string `yaml:"methods"` // names of gRPC methods like CheckVuln, "*" means any
+		Allow   []string `yaml:"allow"`   // empty means any network
+		Deny    []string `yaml:"deny"`
+	}
+
+	APIKey struct {
+		Name  string   `yaml:"name"`
+		Key   string   `yaml:"key"`
+		Roles []string `yaml:"roles"`
+	}
+
+	// JWT tokens are verified by keys of a local JWKS file, which is reloaded on SIGHUP.
+	JWT struct {
+		JWKSPath   string `yaml:"jwks_path"` // empty means JWTs are not accepted
+		Issuer     string `yaml:"issuer"`    // required iss claim, empty means any
+		Audience   string `yaml:"audience"`  // required aud claim, empty means any
+		RolesClaim string `yaml:"roles_claim"`
+	}
+
+	Schedule struct {
+		Name     string        `yaml:"name"`
+		Targets  []string      `yaml:"targets"`
+		TcpPorts []int         `yaml:"tcp_ports"`
+		UdpPorts []int         `yaml:"udp_ports"`
+		Cron     string        `yaml:"cron"`     // either cron or interval must be set
+		Interval time.Duration `yaml:"interval"` // either cron or interval must be set
 	}
 )
 
+// ScriptLookup tells whether vulners.nse does the vulnerabilities lookup instead of the service.
+func (v Vulners) ScriptLookup() bool {
+	return v.Lookup == LookupScript
+}
+
+// NVDProvider tells whether vulnerabilities are searched in NVD feeds instead of vulners API.
+func (v Vulners) NVDProvider() bool {
+	return v.Provider == ProviderNVD
+}
+
+// Enabled tells whether gRPC calls require a token.
+func (a Auth) Enabled() bool {
+	return len(a.APIKeys) > 0 || a.JWT.JWKSPath != ""
+}
+
 func NewConfig(path string) (*Config, error) {
 	yamlFile, err := os.ReadFile(path)
 	if err != nil {
@@ -39,12 +183,40 @@ func NewConfig(path string) (*Config, error) {
 	config := Config{
 		GRPC: GRPC{
 			Port: 3000,
+			TLS: TLS{
+				MinVersion:     "1.2",
+				ReloadInterval: 10 * time.Second,
+			},
 		},
 		Logger: Logger{
 			Level: "info",
 		},
 		Vulners: Vulners{
 			CheckTimeout: time.Minute,
+			Concurrency:  4,
+			Lookup:       LookupScript,
+			Provider:     ProviderVulners,
+			APITimeout:   30 * time.Second,
+			MockAPI: MockAPI{
+				Listen:      "127.0.0.1:0",
+				FixturesDir: "./tests/mock-vulners-api",
+			},
+			Cache: Cache{
+				TTL:           24 * time.Hour,
+				MaxEntries:    10000,
+				StatsInterval: time.Hour,
+			},
+		},
+		Scans: Scans{
+			MaxRunning:       2,
+			Retention:        time.Hour,
+			MaxHosts:         256,
+			NmapServicesPath: "/usr/share/nmap/nmap-services",
+		},
+		Auth: Auth{
+			JWT: JWT{
+				RolesClaim: "roles",
+			},
 		},
 	}
 
@@ -63,6 +235,26 @@ func NewConfig(path string) (*Config, error) {
 		config.GRPC.Port = grpcPortInt
 	}
 
+	gRPCTLSCertFile, ok := os.LookupEnv("GRPC_TLS_CERT_FILE")
+	if ok {
+		config.GRPC.TLS.CertFile = gRPCTLSCertFile
+	}
+
+	gRPCTLSKeyFile, ok := os.LookupEnv("GRPC_TLS_KEY_FILE")
+	if ok {
+		config.GRPC.TLS.KeyFile = gRPCTLSKeyFile
+	}
+
+	gRPCTLSClientCAFile, ok := os.LookupEnv("GRPC_TLS_CLIENT_CA_FILE")
+	if ok {
+		config.GRPC.TLS.ClientCAFile = gRPCTLSClientCAFile
+	}
+
+	gRPCTLSMinVersion, ok := os.LookupEnv("GRPC_TLS_MIN_VERSION")
+	if ok {
+		config.GRPC.TLS.MinVersion = gRPCTLSMinVersion
+	}
+
 	loggerLevel, ok := os.LookupEnv("LOGGER_LEVEL")
 	if ok {
 		config.Logger.Level = loggerLevel
@@ -77,5 +269,312 @@ func NewConfig(path string) (*Config, error) {
 		config.Vulners.CheckTimeout = timeoutParsed
 	}
 
+	vulnersConcurrency, ok := os.LookupEnv("VULNERS_CONCURRENCY")
+	if ok {
+		concurrencyInt, err := strconv.Atoi(vulnersConcurrency)
+		if err != nil {
+			return nil, fmt.Errorf("environment variable VULNERS_CONCURRENCY converting error: %w", err)
+		}
+		config.Vulners.Concurrency = concurrencyInt
+	}
+
+	vulnersStrictParsing, ok := os.LookupEnv("VULNERS_STRICT_PARSING")
+	if ok {
+		strictParsingBool, err := strconv.ParseBool(vulnersStrictParsing)
+		if err != nil {
+			return nil, fmt.Errorf("environment variable VULNERS_STRICT_PARSING parsing error: %w", err)
+		}
+		config.Vulners.StrictParsing = strictParsingBool
+	}
+
+	vulnersLookup, ok := os.LookupEnv("VULNERS_LOOKUP")
+	if ok {
+		config.Vulners.Lookup = vulnersLookup
+	}
+
+	vulnersProvider, ok := os.LookupEnv("VULNERS_PROVIDER")
+	if ok {
+		config.Vulners.Provider = vulnersProvider
+	}
+
+	vulnersAPIURL, ok := os.LookupEnv("VULNERS_API_URL")
+	if ok {
+		config.Vulners.APIURL = vulnersAPIURL
+	}
+
+	vulnersAPIKey, ok := os.LookupEnv("VULNERS_API_KEY")
+	if ok {
+		config.Vulners.APIKey = vulnersAPIKey
+	}
+
+	vulnersAPITimeout, ok := os.LookupEnv("VULNERS_API_TIMEOUT")
+	if ok {
+		timeoutParsed, err := time.ParseDuration(vulnersAPITimeout)
+		if err != nil {
+			return nil, fmt.Errorf("environment variable VULNERS_API_TIMEOUT parsing error: %w", err)
+		}
+		config.Vulners.APITimeout = timeoutParsed
+	}
+
+	vulnersMockAPIEnabled, ok := os.LookupEnv("VULNERS_MOCK_API_ENABLED")
+	if ok {
+		mockAPIEnabledBool, err := strconv.ParseBool(vulnersMockAPIEnabled)
+		if err != nil {
+			return nil, fmt.Errorf("environment variable VULNERS_MOCK_API_ENABLED parsing error: %w", err)
+		}
+		config.Vulners.MockAPI.Enabled = mockAPIEnabledBool
+	}
+
+	vulnersCacheTTL, ok := os.LookupEnv("VULNERS_CACHE_TTL")
+	if ok {
+		ttlParsed, err := time.ParseDuration(vulnersCacheTTL)
+		if err != nil {
+			return nil, fmt.Errorf("environment variable VULNERS_CACHE_TTL parsing error: %w", err)
+		}
+		config.Vulners.Cache.TTL = ttlParsed
+	}
+
+	vulnersCacheMaxEntries, ok := os.LookupEnv("VULNERS_CACHE_MAX_ENTRIES")
+	if ok {
+		maxEntriesInt, err := strconv.Atoi(vulnersCacheMaxEntries)
+		if err != nil {
+			return nil, fmt.Errorf("environment variable VULNERS_CACHE_MAX_ENTRIES converting error: %w", err)
+		}
+		config.Vulners.Cache.MaxEntries = maxEntriesInt
+	}
+
+	vulnersCachePath, ok := os.LookupEnv("VULNERS_CACHE_PATH")
+	if ok {
+		config.Vulners.Cache.Path = vulnersCachePath
+	}
+
+	vulnersCacheStatsInterval, ok := os.LookupEnv("VULNERS_CACHE_STATS_INTERVAL")
+	if ok {
+		intervalParsed, err := time.ParseDuration(vulnersCacheStatsInterval)
+		if err != nil {
+			return nil, fmt.Errorf("environment variable VULNERS_CACHE_STATS_INTERVAL parsing error: %w", err)
+		}
+		config.Vulners.Cache.StatsInterval = intervalParsed
+	}
+
+	scansMaxRunning, ok := os.LookupEnv("SCANS_MAX_RUNNING")
+	if ok {
+		maxRunningInt, err := strconv.Atoi(scansMaxRunning)
+		if err != nil {
+			return nil, fmt.Errorf("environment variable SCANS_MAX_RUNNING converting error: %w", err)
+		}
+		config.Scans.MaxRunning = maxRunningInt
+	}
+
+	scansRetention, ok := os.LookupEnv("SCANS_RETENTION")
+	if ok {
+		retentionParsed, err := time.ParseDuration(scansRetention)
+		if err != nil {
+			return nil, fmt.Errorf("environment variable SCANS_RETENTION parsing error: %w", err)
+		}
+		config.Scans.Retention = retentionParsed
+	}
+
+	scansMaxHosts, ok := os.LookupEnv("SCANS_MAX_HOSTS")
+	if ok {
+		maxHostsInt, err := strconv.Atoi(scansMaxHosts)
+		if err != nil {
+			return nil, fmt.Errorf("environment variable SCANS_MAX_HOSTS converting error: %w", err)
+		}
+		config.Scans.MaxHosts = maxHostsInt
+	}
+
+	scansDefaultTcpPorts, ok := os.LookupEnv("SCANS_DEFAULT_TCP_PORTS")
+	if ok {
+		config.Scans.DefaultTcpPorts = splitList(scansDefaultTcpPorts)
+	}
+
+	scansNmapServicesPath, ok := os.LookupEnv("SCANS_NMAP_SERVICES_PATH")
+	if ok {
+		config.Scans.NmapServicesPath = scansNmapServicesPath
+	}
+
+	scopeAllow, ok := os.LookupEnv("SCOPE_ALLOW")
+	if ok {
+		config.Scope.Allow = splitList(scopeAllow)
+	}
+
+	scopeDeny, ok := os.LookupEnv("SCOPE_DENY")
+	if ok {
+		config.Scope.Deny = splitList(scopeDeny)
+	}
+
+	authJWTJWKSPath, ok := os.LookupEnv("AUTH_JWT_JWKS_PATH")
+	if ok {
+		config.Auth.JWT.JWKSPath = authJWTJWKSPath
+	}
+
+	authJWTIssuer, ok := os.LookupEnv("AUTH_JWT_ISSUER")
+	if ok {
+		config.Auth.JWT.Issuer = authJWTIssuer
+	}
+
+	authJWTAudience, ok := os.LookupEnv("AUTH_JWT_AUDIENCE")
+	if ok {
+		config.Auth.JWT.Audience = authJWTAudience
+	}
+
+	storagePath, ok := os.LookupEnv("STORAGE_PATH")
+	if ok {
+		config.Storage.Path = storagePath
+	}
+
+	nvdFeedsDir, ok := os.LookupEnv("NVD_FEEDS_DIR")
+	if ok {
+		config.NVD.FeedsDir = nvdFeedsDir
+	}
+
+	enrichmentKEVPath, ok := os.LookupEnv("ENRICHMENT_KEV_PATH")
+	if ok {
+		config.Enrichment.KEVPath = enrichmentKEVPath
+	}
+
+	enrichmentEPSSPath, ok := os.LookupEnv("ENRICHMENT_EPSS_PATH")
+	if ok {
+		config.Enrichment.EPSSPath = enrichmentEPSSPath
+	}
+
+	// Validate values
+	if (config.GRPC.TLS.CertFile == "") != (config.GRPC.TLS.KeyFile == "") {
+		return nil, fmt.Errorf("grpc tls cert_file and key_file must be set together")
+	}
+	if config.GRPC.TLS.ClientCAFile != "" && config.GRPC.TLS.CertFile == "" {
+		return nil, fmt.Errorf("grpc tls client_ca_file requires cert_file and key_file")
+	}
+	if config.GRPC.TLS.MinVersion != "1.2" && config.GRPC.TLS.MinVersion != "1.3" {
+		return nil, fmt.Errorf("grpc tls min_version must be \"1.2\" or \"1.3\", got %q", config.GRPC.TLS.MinVersion)
+	}
+	if config.GRPC.TLS.ReloadInterval < 0 {
+		return nil, fmt.Errorf("grpc tls reload_interval must not be negative, got %s", config.GRPC.TLS.ReloadInterval)
+	}
+	if config.Vulners.Concurrency < 1 {
+		return nil, fmt.Errorf("vulners concurrency must be positive, got %d", config.Vulners.Concurrency)
+	}
+	if config.Vulners.Lookup != LookupNative && config.Vulners.Lookup != LookupScript {
+		return nil, fmt.Errorf("vulners lookup must be %q or %q, got %q", LookupNative, LookupScript, config.Vulners.Lookup)
+	}
+	if config.Vulners.Provider != ProviderVulners && config.Vulners.Provider != ProviderNVD {
+		return nil, fmt.Errorf("vulners provider must be %q or %q, got %q", ProviderVulners, ProviderNVD, config.Vulners.Provider)
+	}
+	if config.Vulners.Provider == ProviderNVD {
+		if config.Vulners.Lookup != LookupNative {
+			return nil, fmt.Errorf("vulners provider %q requires %q lookup", ProviderNVD, LookupNative)
+		}
+		if config.NVD.FeedsDir == "" {
+			return nil, fmt.Errorf("nvd feeds_dir is required for vulners provider %q", ProviderNVD)
+		}
+	}
+	if config.Vulners.Cache.TTL < 0 {
+		return nil, fmt.Errorf("vulners cache ttl must not be negative, got %s", config.Vulners.Cache.TTL)
+	}
+	if config.Vulners.Cache.StatsInterval < 0 {
+		return nil, fmt.Errorf("vulners cache stats_interval must not be negative, got %s", config.Vulners.Cache.StatsInterval)
+	}
+	if config.Vulners.Cache.TTL > 0 && config.Vulners.Cache.MaxEntries < 1 {
+		return nil, fmt.Errorf("vulners cache max_entries must be positive, got %d", config.Vulners.Cache.MaxEntries)
+	}
+	if config.Scans.MaxRunning < 1 {
+		return nil, fmt.Errorf("scans max_running must be positive, got %d", config.Scans.MaxRunning)
+	}
+	if config.Scans.MaxHosts < 1 {
+		return nil, fmt.Errorf("scans max_hosts must be positive, got %d", config.Scans.MaxHosts)
+	}
+	clientScopeNames := make(map[string]bool, len(config.Scope.Clients))
+	for i, clientScope := range config.Scope.Clients {
+		if clientScope.Name == "" {
+			return nil, fmt.Errorf("scope client #%d: name is required", i+1)
+		}
+		if clientScopeNames[clientScope.Name] {
+			return nil, fmt.Errorf("scope client %q: duplicate name", clientScope.Name)
+		}
+		clientScopeNames[clientScope.Name] = true
+		if len(clientScope.Addresses) == 0 {
+			return nil, fmt.Errorf("scope client %q: addresses is required", clientScope.Name)
+		}
+	}
+	roleNames := make(map[string]bool, len(config.Auth.Roles))
+	for i, role := range config.Auth.Roles {
+		if role.Name == "" {
+			return nil, fmt.Errorf("auth role #%d: name is required", i+1)
+		}
+		if roleNames[role.Name] {
+			return nil, fmt.Errorf("auth role %q: duplicate name", role.Name)
+		}
+		roleNames[role.Name] = true
+		if len(role.Methods) == 0 {
+			return nil, fmt.Errorf("auth role %q: methods is required", role.Name)
+		}
+	}
+	apiKeyNames := make(map[string]bool, len(config.Auth.APIKeys))
+	for i, apiKey := range config.Auth.APIKeys {
+		if apiKey.Name == "" {
+			return nil, fmt.Errorf("auth api key #%d: name is required", i+1)
+		}
+		if apiKeyNames[apiKey.Name] {
+			return nil, fmt.Errorf("auth api key %q: duplicate name", apiKey.Name)
+		}
+		apiKeyNames[apiKey.Name] = true
+		if len(apiKey.Key) < 16 {
+			return nil, fmt.Errorf("auth api key %q: key must be at least 16 characters long", apiKey.Name)
+		}
+		if len(apiKey.Roles) == 0 {
+			return nil, fmt.Errorf("auth api key %q: roles is required", apiKey.Name)
+		}
+		for _, role := range apiKey.Roles {
+			if !roleNames[role] {
+				return nil, fmt.Errorf("auth api key %q: unknown role %q", apiKey.Name, role)
+			}
+		}
+	}
+	if config.Auth.Enabled() && len(config.Auth.Roles) == 0 {
+		return nil, fmt.Errorf("auth roles are required when api_keys or jwt are set")
+	}
+	if config.Auth.JWT.JWKSPath != "" && config.Auth.JWT.RolesClaim == "" {
+		return nil, fmt.Errorf("auth jwt roles_claim cannot be empty")
+	}
+	scheduleNames := make(map[string]bool, len(config.Schedules))
+	for i, schedule := range config.Schedules {
+		if schedule.Name == "" {
+			return nil, fmt.Errorf("schedule #%d: name is required", i+1)
+		}
+		if scheduleNames[schedule.Name] {
+			return nil, fmt.Errorf("schedule %q: duplicate name", schedule.Name)
+		}
+		scheduleNames[schedule.Name] = true
+		if len(schedule.Targets) == 0 {
+			return nil, fmt.Errorf("schedule %q: targets is required", schedule.Name)
+		}
+		if (schedule.Cron == "") == (schedule.Interval == 0) {
+			return nil, fmt.Errorf("schedule %q: exactly one of cron and interval must be set", schedule.Name)
+		}
+		for _, port := range schedule.TcpPorts {
+			if port < 1 || port > 65535 {
+				return nil, fmt.Errorf("schedule %q: invalid tcp port %d", schedule.Name, port)
+			}
+		}
+		for _, port := range schedule.UdpPorts {
+			if port < 1 || port > 65535 {
+				return nil, fmt.Errorf("schedule %q: invalid udp port %d", schedule.Name, port)
+			}
+		}
+	}
+
 	return &config, nil
 }
+
+// splitList splits a comma separated environment variable value, empty items are skipped.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,204 @@
+// Package auth authenticates gRPC calls by bearer tokens, static API keys or JWTs, and authorizes them by roles
+// allowing certain methods and target networks.
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/NikolaB131/nmap-vulners-service/internal/target"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// AnyMethod in Role.Methods allows every method of the service
+const AnyMethod = "*"
+
+const bearerPrefix = "bearer "
+
+var ErrInvalidToken = errors.New("invalid token")
+
+// Role allows calling Methods, given by names like "CheckVuln", and scanning targets within Scope.
+type Role struct {
+	Name    string
+	Methods []string
+	Scope   *target.Scope // nil means any target, the global scope still applies
+}
+
+func (r Role) allowsMethod(method string) bool {
+	return slices.Contains(r.Methods, AnyMethod) || slices.Contains(r.Methods, method)
+}
+
+// APIKey is a static token, Roles are names of roles granted to its holder.
+type APIKey struct {
+	Name  string
+	Key   string
+	Roles []string
+}
+
+// Identity of an authenticated client. In a call context Roles has only roles which allow the called method.
+type Identity struct {
+	Name  string // API key name or JWT subject
+	Roles []Role
+}
+
+// Forbidden returns values of targets which no role of the identity allows to scan.
+func (i Identity) Forbidden(targets []target.Target) []string {
+	var forbidden []string
+	for _, t := range targets {
+		allowed := false
+		for _, role := range i.Roles {
+			if role.Scope == nil || role.Scope.Allows(t) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			forbidden = append(forbidden, t.Value)
+		}
+	}
+	return forbidden
+}
+
+type identityKey struct{}
+
+// FromContext returns the identity of the client making the call, ok is false if authentication is disabled.
+func FromContext(ctx context.Context) (Identity, bool) {
+	identity, ok := ctx.Value(identityKey{}).(Identity)
+	return identity, ok
+}
+
+// Authenticator checks the "authorization: Bearer <token>" metadata of every call of a service.
+type Authenticator struct {
+	log   *slog.Logger
+	keys  map[[sha256.Size]byte]APIKey // by key hash, so lookup time does not depend on how much of a key matches
+	jwt   *JWTVerifier                 // nil if JWTs are not accepted
+	roles map[string]Role
+}
+
+// NewAuthenticator checks that roles refer to methods of service and API keys refer to existing roles, jwt can be nil.
+func NewAuthenticator(logger *slog.Logger, service *grpc.ServiceDesc, roles []Role, keys []APIKey, jwt *JWTVerifier) (*Authenticator, error) {
+	methods := make([]string, 0, len(service.Methods)+len(service.Streams))
+	for _, method := range service.Methods {
+		methods = append(methods, method.MethodName)
+	}
+	for _, stream := range service.Streams {
+		methods = append(methods, stream.StreamName)
+	}
+
+	a := &Authenticator{
+		log:   logger,
+		keys:  make(map[[sha256.Size]byte]APIKey, len(keys)),
+		jwt:   jwt,
+		roles: make(map[string]Role, len(roles)),
+	}
+	for _, role := range roles {
+		if _, ok := a.roles[role.Name]; ok {
+			return nil, fmt.Errorf("role %q: duplicate name", role.Name)
+		}
+		for _, method := range role.Methods {
+			if method != AnyMethod && !slices.Contains(methods, method) {
+				return nil, fmt.Errorf("role %q: unknown method %q, possible values: %s, %s", role.Name, method, AnyMethod, strings.Join(methods, ", "))
+			}
+		}
+		a.roles[role.Name] = role
+	}
+	for _, key := range keys {
+		for _, roleName := range key.Roles {
+			if _, ok := a.roles[roleName]; !ok {
+				return nil, fmt.Errorf("api key %q: unknown role %q", key.Name, roleName)
+			}
+		}
+		hash := sha256.Sum256([]byte(key.Key))
+		if _, ok := a.keys[hash]; ok {
+			return nil, fmt.Errorf("api key %q: the same key is used by another api key", key.Name)
+		}
+		a.keys[hash] = key
+	}
+	return a, nil
+}
+
+func (a *Authenticator) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := a.authorize(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func (a *Authenticator) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := a.authorize(stream.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authorizedStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+// authorizedStream passes the identity to the handler through the stream context
+type authorizedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authorizedStream) Context() context.Context {
+	return s.ctx
+}
+
+// authorize returns ctx with the identity of the client, fullMethod is like "/NetVulnService/CheckVuln".
+func (a *Authenticator) authorize(ctx context.Context, fullMethod string) (context.Context, error) {
+	token, ok := bearerToken(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "bearer token is required in authorization metadata")
+	}
+	name, roleNames, err := a.authenticate(token)
+	if err != nil {
+		a.log.Warn("authentication failed", slog.String("method", fullMethod), slog.String("reason", err.Error()))
+		return nil, status.Error(codes.Unauthenticated, ErrInvalidToken.Error())
+	}
+
+	method := path.Base(fullMethod)
+	identity := Identity{Name: name}
+	for _, roleName := range roleNames {
+		role, ok := a.roles[roleName]
+		if ok && role.allowsMethod(method) {
+			identity.Roles = append(identity.Roles, role)
+		}
+	}
+	if len(identity.Roles) == 0 {
+		return nil, status.Errorf(codes.PermissionDenied, "%s is not allowed to call %s", name, method)
+	}
+
+	return context.WithValue(ctx, identityKey{}, identity), nil
+}
+
+// authenticate returns the client name and names of its roles. Roles of JWTs missing in the configuration are ignored.
+func (a *Authenticator) authenticate(token string) (string, []string, error) {
+	key, ok := a.keys[sha256.Sum256([]byte(token))]
+	if ok {
+		return key.Name, key.Roles, nil
+	}
+	if a.jwt == nil || strings.Count(token, ".") != 2 {
+		return "", nil, errors.New("unknown api key")
+	}
+	return a.jwt.Verify(token)
+}
+
+func bearerToken(ctx context.Context) (string, bool) {
+	values := metadata.ValueFromIncomingContext(ctx, "authorization")
+	if len(values) != 1 || len(values[0]) <= len(bearerPrefix) || !strings.EqualFold(values[0][:len(bearerPrefix)], bearerPrefix) {
+		return "", false
+	}
+	return strings.TrimSpace(values[0][len(bearerPrefix):]), true
+}
@@ -0,0 +1,240 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// leeway allows small clock differences between the service and the token issuer
+const leeway = 30 * time.Second
+
+// signingMethods are asymmetric algorithms JWTs can be signed with, HMAC is not accepted as JWKS keys are public
+var signingMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+
+// JWTVerifier verifies JWTs signed by keys from a local JWKS file (RFC 7517), e.g. downloaded from the identity provider.
+type JWTVerifier struct {
+	log        *slog.Logger
+	path       string
+	rolesClaim string
+	parser     *jwt.Parser
+
+	mu   sync.RWMutex
+	keys []jwk
+}
+
+type jwk struct {
+	kid string
+	alg string // empty means any algorithm suitable for the key type
+	key crypto.PublicKey
+}
+
+type jwkJSON struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// NewJWTVerifier reads keys from jwksPath. Tokens must have exp claim and, if set, issuer and audience.
+// rolesClaim is a claim with role names, either a list or a space separated string.
+func NewJWTVerifier(logger *slog.Logger, jwksPath string, issuer string, audience string, rolesClaim string) (*JWTVerifier, error) {
+	options := []jwt.ParserOption{jwt.WithValidMethods(signingMethods), jwt.WithExpirationRequired(), jwt.WithIssuedAt(), jwt.WithLeeway(leeway)}
+	if issuer != "" {
+		options = append(options, jwt.WithIssuer(issuer))
+	}
+	if audience != "" {
+		options = append(options, jwt.WithAudience(audience))
+	}
+
+	v := &JWTVerifier{log: logger, path: jwksPath, rolesClaim: rolesClaim, parser: jwt.NewParser(options...)}
+	err := v.Reload()
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Reload reads the JWKS file again, e.g. after keys rotation, the previous keys are kept if it cannot be read.
+func (v *JWTVerifier) Reload() error {
+	data, err := os.ReadFile(v.path)
+	if err != nil {
+		return fmt.Errorf("unable to read jwks: %w", err)
+	}
+	var set struct {
+		Keys []jwkJSON `json:"keys"`
+	}
+	err = json.Unmarshal(data, &set)
+	if err != nil {
+		return fmt.Errorf("unable to decode jwks: %w", err)
+	}
+
+	var keys []jwk
+	for i, raw := range set.Keys {
+		if raw.Use != "" && raw.Use != "sig" {
+			continue
+		}
+		key, err := raw.publicKey()
+		if err != nil {
+			return fmt.Errorf("jwks key #%d %q: %w", i+1, raw.Kid, err)
+		}
+		if key == nil {
+			v.log.Warn("jwks key of unsupported type is skipped", slog.String("kid", raw.Kid), slog.String("kty", raw.Kty))
+			continue
+		}
+		keys = append(keys, jwk{kid: raw.Kid, alg: raw.Alg, key: key})
+	}
+	if len(keys) == 0 {
+		return errors.New("jwks has no signature keys")
+	}
+
+	v.mu.Lock()
+	v.keys = keys
+	v.mu.Unlock()
+
+	v.log.Info("JWKS loaded", slog.String("path", v.path), slog.Int("keys", len(keys)))
+	return nil
+}
+
+// Verify returns the subject and role names of a valid token.
+func (v *JWTVerifier) Verify(token string) (string, []string, error) {
+	claims := jwt.MapClaims{}
+	_, err := v.parser.ParseWithClaims(token, claims, v.key)
+	if err != nil {
+		return "", nil, err
+	}
+
+	subject, err := claims.GetSubject()
+	if err != nil || subject == "" {
+		return "", nil, errors.New("token has no sub claim")
+	}
+
+	var roles []string
+	switch value := claims[v.rolesClaim].(type) {
+	case string:
+		roles = strings.Fields(value)
+	case []any:
+		for _, item := range value {
+			role, ok := item.(string)
+			if ok {
+				roles = append(roles, role)
+			}
+		}
+	}
+	return subject, roles, nil
+}
+
+// key finds the verification key by kid header, a token without kid can be verified only if JWKS has a single key.
+func (v *JWTVerifier) key(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	for _, key := range v.keys {
+		if key.kid != kid && !(kid == "" && len(v.keys) == 1) {
+			continue
+		}
+		if key.alg != "" && key.alg != token.Method.Alg() {
+			return nil, fmt.Errorf("key %q is for %s, token is signed with %s", key.kid, key.alg, token.Method.Alg())
+		}
+		return key.key, nil
+	}
+	return nil, fmt.Errorf("unknown key %q", kid)
+}
+
+// publicKey returns nil for key types which cannot verify signatures of signingMethods.
+func (k jwkJSON) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("n: %w", err)
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("e: %w", err)
+		}
+		if !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 || n.BitLen() < 2048 {
+			return nil, errors.New("rsa key must have at least 2048 bits and a valid exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		var ecdhCurve ecdh.Curve
+		switch k.Crv {
+		case "P-256":
+			curve, ecdhCurve = elliptic.P256(), ecdh.P256()
+		case "P-384":
+			curve, ecdhCurve = elliptic.P384(), ecdh.P384()
+		case "P-521":
+			curve, ecdhCurve = elliptic.P521(), ecdh.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("x: %w", err)
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("y: %w", err)
+		}
+		size := (curve.Params().BitSize + 7) / 8
+		if len(x) != size || len(y) != size {
+			return nil, fmt.Errorf("coordinates must be %d bytes long", size)
+		}
+		// ecdh validates that the point is on the curve
+		_, err = ecdhCurve.NewPublicKey(append(append([]byte{4}, x...), y...))
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("x: %w", err)
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("x must be %d bytes long", ed25519.PublicKeySize)
+		}
+		return ed25519.PublicKey(x), nil
+	}
+	return nil, nil
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, errors.New("empty value")
+	}
+	return new(big.Int).SetBytes(data), nil
+}
@@ -0,0 +1,232 @@
+package grpc
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/NikolaB131/nmap-vulners-service/internal/entity"
+	"github.com/NikolaB131/nmap-vulners-service/internal/target"
+	nmap_vulners_service "github.com/NikolaB131/nmap-vulners-service/pkg/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var scanStatuses = map[entity.ScanStatus]nmap_vulners_service.ScanStatus{
+	entity.ScanStatusQueued:    nmap_vulners_service.ScanStatus_SCAN_STATUS_QUEUED,
+	entity.ScanStatusRunning:   nmap_vulners_service.ScanStatus_SCAN_STATUS_RUNNING,
+	entity.ScanStatusDone:      nmap_vulners_service.ScanStatus_SCAN_STATUS_DONE,
+	entity.ScanStatusFailed:    nmap_vulners_service.ScanStatus_SCAN_STATUS_FAILED,
+	entity.ScanStatusCancelled: nmap_vulners_service.ScanStatus_SCAN_STATUS_CANCELLED,
+}
+
+func scanToProto(scan entity.Scan) *nmap_vulners_service.Scan {
+	var summary *nmap_vulners_service.ScanSummary
+	if scan.Status == entity.ScanStatusDone {
+		summary = scanStatsToProto(scan.Stats)
+	}
+
+	return &nmap_vulners_service.Scan{
+		Id:            scan.ID,
+		Status:        scanStatuses[scan.Status],
+		Targets:       scan.Targets,
+		TcpPorts:      portsToProto(scan.TcpPorts),
+		UdpPorts:      portsToProto(scan.UdpPorts),
+		TcpPortRanges: scan.TcpPorts,
+		UdpPortRanges: scan.UdpPorts,
+		CreatedAt:     timeToProto(scan.CreatedAt),
+		StartedAt:     timeToProto(scan.StartedAt),
+		FinishedAt:    timeToProto(scan.FinishedAt),
+		Error:         scan.Error,
+		Results:       hostsResultsToProto(scan.Results),
+		Summary:       summary,
+	}
+}
+
+// portsToProto returns single ports, ranges are given as is in port ranges fields.
+func portsToProto(ports []string) []int32 {
+	protoPorts := make([]int32, 0, len(ports))
+	for _, port := range ports {
+		portInt, err := strconv.Atoi(port)
+		if err != nil {
+			continue
+		}
+		protoPorts = append(protoPorts, int32(portInt))
+	}
+	return protoPorts
+}
+
+func targetsToProto(targets []target.Target) []*nmap_vulners_service.ResolvedTarget {
+	protoTargets := make([]*nmap_vulners_service.ResolvedTarget, len(targets))
+	for i, resolved := range targets {
+		addresses := make([]string, len(resolved.Addresses))
+		for j, address := range resolved.Addresses {
+			addresses[j] = address.String()
+		}
+		protoTargets[i] = &nmap_vulners_service.ResolvedTarget{
+			Target:       resolved.Value,
+			Kind:         string(resolved.Kind),
+			Hosts:        resolved.Hosts,
+			Addresses:    addresses,
+			ResolveError: resolved.ResolveError,
+		}
+	}
+	return protoTargets
+}
+
+func scanStatsToProto(stats entity.ScanStats) *nmap_vulners_service.ScanSummary {
+	return &nmap_vulners_service.ScanSummary{
+		Elapsed:    durationpb.New(stats.Elapsed),
+		HostsUp:    int32(stats.HostsUp),
+		HostsDown:  int32(stats.HostsDown),
+		HostsTotal: int32(stats.HostsTotal),
+	}
+}
+
+func timeToProto(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+func hostsResultsToProto(hostsResults []entity.HostResult) []*nmap_vulners_service.TargetsResult {
+	results := make([]*nmap_vulners_service.TargetsResult, len(hostsResults))
+
+	for i, host := range hostsResults {
+		target := &nmap_vulners_service.TargetsResult{
+			Target:    host.TargetIP,
+			Services:  servicesToProto(host.Services),
+			Status:    host.Status,
+			Hostnames: host.Hostnames,
+			Error:     host.Error,
+		}
+		if host.OS.Name != "" {
+			target.Os = &nmap_vulners_service.OSGuess{Name: host.OS.Name, Accuracy: int32(host.OS.Accuracy)}
+		}
+
+		for _, warning := range host.Warnings {
+			target.ParseWarnings = append(target.ParseWarnings, &nmap_vulners_service.ParseWarning{
+				TcpPort:    int32(warning.TcpPort),
+				Port:       int32(warning.TcpPort),
+				Protocol:   warning.Protocol,
+				Cpe:        warning.CPE,
+				Identifier: warning.Identifier,
+				Field:      warning.Field,
+				Value:      warning.Value,
+				Message:    warning.Message,
+			})
+		}
+		results[i] = target
+	}
+
+	return results
+}
+
+func servicesToProto(services []entity.Service) []*nmap_vulners_service.Service {
+	protoServices := make([]*nmap_vulners_service.Service, len(services))
+
+	for i, service := range services {
+		protoServices[i] = &nmap_vulners_service.Service{
+			Name:         service.Name,
+			Version:      service.Version,
+			TcpPort:      int32(service.TcpPort),
+			Port:         int32(service.TcpPort),
+			Cpes:         service.CPEs,
+			Vulns:        vulnsToProto(service.Vulns),
+			Protocol:     service.Protocol,
+			State:        service.State,
+			Product:      service.Product,
+			ExtraInfo:    service.ExtraInfo,
+			ScriptStatus: service.ScriptStatus,
+		}
+	}
+
+	return protoServices
+}
+
+func vulnsToProto(vulns []entity.Vulnerability) []*nmap_vulners_service.Vulnerability {
+	protoVulns := make([]*nmap_vulners_service.Vulnerability, len(vulns))
+
+	for i, vuln := range vulns {
+		protoVulns[i] = &nmap_vulners_service.Vulnerability{
+			Identifier:     vuln.Identifier,
+			CvssScore:      vuln.CvssScore,
+			Type:           vuln.Type,
+			IsExploit:      vuln.IsExploit,
+			Url:            vuln.URL,
+			Cpe:            vuln.CPE,
+			InKev:          vuln.InKEV,
+			KevDateAdded:   timeToProto(vuln.KEVDateAdded),
+			Epss:           vuln.EPSS,
+			EpssPercentile: vuln.EPSSPercentile,
+		}
+	}
+
+	return protoVulns
+}
+
+func scanDiffToProto(diff entity.ScanDiff) *nmap_vulners_service.DiffScansResponse {
+	response := &nmap_vulners_service.DiffScansResponse{Hosts: make([]*nmap_vulners_service.HostDiff, len(diff.Hosts))}
+
+	for i, host := range diff.Hosts {
+		hostDiff := &nmap_vulners_service.HostDiff{
+			Target:          host.TargetIP,
+			OpenedPorts:     servicesToProto(host.OpenedPorts),
+			ClosedPorts:     servicesToProto(host.ClosedPorts),
+			ChangedServices: make([]*nmap_vulners_service.ServiceDiff, len(host.Services)),
+		}
+
+		for j, service := range host.Services {
+			hostDiff.ChangedServices[j] = &nmap_vulners_service.ServiceDiff{
+				Protocol:      service.Protocol,
+				TcpPort:       int32(service.TcpPort),
+				Port:          int32(service.TcpPort),
+				Name:          service.Name,
+				OldVersion:    service.OldVersion,
+				NewVersion:    service.NewVersion,
+				NewVulns:      vulnsToProto(service.NewVulns),
+				ResolvedVulns: vulnsToProto(service.ResolvedVulns),
+			}
+		}
+		response.Hosts[i] = hostDiff
+	}
+
+	return response
+}
+
+var vulnsSorts = map[nmap_vulners_service.VulnsSort]entity.VulnsSort{
+	nmap_vulners_service.VulnsSort_VULNS_SORT_UNSPECIFIED: entity.VulnsSortNone,
+	nmap_vulners_service.VulnsSort_VULNS_SORT_CVSS:        entity.VulnsSortCVSS,
+	nmap_vulners_service.VulnsSort_VULNS_SORT_EPSS:        entity.VulnsSortEPSS,
+}
+
+func vulnsFilterFromProto(req *nmap_vulners_service.CheckVulnRequest) (entity.VulnsFilter, error) {
+	// NaN passes any comparison and would filter out every vulnerability
+	if math.IsNaN(float64(req.GetMinCvss())) || req.GetMinCvss() < 0 || req.GetMinCvss() > 10 {
+		return entity.VulnsFilter{}, status.Error(codes.InvalidArgument, "min_cvss must be between 0 and 10")
+	}
+	if req.GetMaxPerService() < 0 {
+		return entity.VulnsFilter{}, status.Error(codes.InvalidArgument, "max_per_service cannot be negative")
+	}
+	if math.IsNaN(float64(req.GetMinEpss())) || req.GetMinEpss() < 0 || req.GetMinEpss() > 1 {
+		return entity.VulnsFilter{}, status.Error(codes.InvalidArgument, "min_epss must be between 0 and 1")
+	}
+	sort, ok := vulnsSorts[req.GetSort()]
+	if !ok {
+		return entity.VulnsFilter{}, status.Error(codes.InvalidArgument, "unknown sort")
+	}
+
+	return entity.VulnsFilter{
+		MinCVSS:       req.GetMinCvss(),
+		OnlyExploits:  req.GetOnlyExploits(),
+		IncludeTypes:  req.GetIncludeTypes(),
+		ExcludeTypes:  req.GetExcludeTypes(),
+		OnlyKEV:       req.GetOnlyKev(),
+		MinEPSS:       req.GetMinEpss(),
+		Sort:          sort,
+		MaxPerService: int(req.GetMaxPerService()),
+	}, nil
+}
@@ -3,83 +3,407 @@ package grpc
 import (
 	"context"
 	"errors"
+	"net/netip"
+	"slices"
 	"strconv"
+	"strings"
+	"sync"
 
+	"github.com/NikolaB131/nmap-vulners-service/internal/auth"
 	"github.com/NikolaB131/nmap-vulners-service/internal/entity"
+	"github.com/NikolaB131/nmap-vulners-service/internal/ports"
+	"github.com/NikolaB131/nmap-vulners-service/internal/repository"
 	"github.com/NikolaB131/nmap-vulners-service/internal/service"
+	"github.com/NikolaB131/nmap-vulners-service/internal/target"
 	nmap_vulners_service "github.com/NikolaB131/nmap-vulners-service/pkg/proto"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 )
 
-type VulnersService interface {
-	CheckVuln(ctx context.Context, targets []string, tcpPorts []string) ([]entity.HostResult, error)
+type ScansService interface {
+	Run(ctx context.Context, targets []string, addresses map[string][]string, tcpPorts []string, udpPorts []string, filter entity.VulnsFilter, onTargetDone service.TargetDoneFunc) (entity.Scan, error)
+	Start(targets []string, addresses map[string][]string, tcpPorts []string, udpPorts []string, filter entity.VulnsFilter) (entity.Scan, error)
+	Get(ctx context.Context, id string) (entity.Scan, error)
+	List(ctx context.Context, filter repository.ScanFilter) ([]entity.Scan, error)
+	Diff(ctx context.Context, oldID string, newID string) (entity.ScanDiff, error)
+	Import(ctx context.Context, data []byte) (entity.Scan, error)
+	Cancel(id string) (entity.Scan, error)
 }
 
 type GRPCController struct {
 	nmap_vulners_service.UnimplementedNetVulnServiceServer
-	vulners VulnersService
+	scans   ScansService
+	targets *target.Parser
+	scope   *target.Policy
+	ports   *ports.Parser
 }
 
-func Register(gRPCServer *grpc.Server, vulners VulnersService) {
-	nmap_vulners_service.RegisterNetVulnServiceServer(gRPCServer, &GRPCController{vulners: vulners})
+func Register(gRPCServer *grpc.Server, scans ScansService, targets *target.Parser, scope *target.Policy, ports *ports.Parser) {
+	nmap_vulners_service.RegisterNetVulnServiceServer(gRPCServer, &GRPCController{scans: scans, targets: targets, scope: scope, ports: ports})
 }
 
 func (c *GRPCController) CheckVuln(ctx context.Context, req *nmap_vulners_service.CheckVulnRequest) (*nmap_vulners_service.CheckVulnResponse, error) {
-	ports := req.GetTcpPorts()
-	targets := req.GetTargets()
+	targets, tcpPorts, udpPorts, err := c.parseCheckVulnRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	filter, err := vulnsFilterFromProto(req)
+	if err != nil {
+		return nil, err
+	}
+
+	scan, err := c.scans.Run(ctx, req.GetTargets(), target.HostnameAddresses(targets), tcpPorts, udpPorts, filter, nil)
+	if err != nil {
+		return nil, checkVulnError(err)
+	}
+
+	return &nmap_vulners_service.CheckVulnResponse{
+		Results: hostsResultsToProto(scan.Results),
+		Targets: targetsToProto(targets),
+	}, nil
+}
+
+func (c *GRPCController) CheckVulnStream(req *nmap_vulners_service.CheckVulnRequest, stream nmap_vulners_service.NetVulnService_CheckVulnStreamServer) error {
+	targets, tcpPorts, udpPorts, err := c.parseCheckVulnRequest(stream.Context(), req)
+	if err != nil {
+		return err
+	}
+	filter, err := vulnsFilterFromProto(req)
+	if err != nil {
+		return err
+	}
 
-	if len(targets) == 0 {
-		return nil, status.Error(codes.InvalidArgument, "targets is required")
+	err = stream.Send(&nmap_vulners_service.CheckVulnStreamResponse{
+		Payload: &nmap_vulners_service.CheckVulnStreamResponse_Targets{
+			Targets: &nmap_vulners_service.ResolvedTargets{Targets: targetsToProto(targets)},
+		},
+	})
+	if err != nil {
+		return err
 	}
-	for _, target := range targets {
-		if len(target) == 0 {
-			return nil, status.Error(codes.InvalidArgument, "target cannot be an empty string")
+
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
+	// Targets finish in parallel, but a stream must not be written from several goroutines at once
+	var sendMu sync.Mutex
+	var sendErr error
+	scan, err := c.scans.Run(ctx, req.GetTargets(), target.HostnameAddresses(targets), tcpPorts, udpPorts, filter, func(_ string, hostsResults []entity.HostResult) {
+		sendMu.Lock()
+		defer sendMu.Unlock()
+
+		for _, result := range hostsResultsToProto(hostsResults) {
+			if sendErr != nil {
+				return
+			}
+			sendErr = stream.Send(&nmap_vulners_service.CheckVulnStreamResponse{
+				Payload: &nmap_vulners_service.CheckVulnStreamResponse_Result{Result: result},
+			})
+			if sendErr != nil {
+				cancel() // nobody is listening anymore, stop scanning
+			}
 		}
+	})
+	if sendErr != nil {
+		return sendErr
+	}
+	if err != nil {
+		return checkVulnError(err)
 	}
 
-	convertedPorts := make([]string, len(ports))
-	for i := 0; i < len(ports); i++ {
-		convertedPorts[i] = strconv.Itoa(int(ports[i]))
+	return stream.Send(&nmap_vulners_service.CheckVulnStreamResponse{
+		Payload: &nmap_vulners_service.CheckVulnStreamResponse_Summary{Summary: scanStatsToProto(scan.Stats)},
+	})
+}
+
+func (c *GRPCController) StartScan(ctx context.Context, req *nmap_vulners_service.CheckVulnRequest) (*nmap_vulners_service.StartScanResponse, error) {
+	targets, tcpPorts, udpPorts, err := c.parseCheckVulnRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	filter, err := vulnsFilterFromProto(req)
+	if err != nil {
+		return nil, err
+	}
+
+	scan, err := c.scans.Start(req.GetTargets(), target.HostnameAddresses(targets), tcpPorts, udpPorts, filter)
+	if err != nil {
+		if errors.Is(err, service.ErrPrivilegesRequired) {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+		return nil, status.Error(codes.Internal, "failed to start scan")
 	}
 
-	checkVulnResult, err := c.vulners.CheckVuln(ctx, req.GetTargets(), convertedPorts)
+	return &nmap_vulners_service.StartScanResponse{ScanId: scan.ID, Targets: targetsToProto(targets)}, nil
+}
+
+func (c *GRPCController) GetScan(ctx context.Context, req *nmap_vulners_service.GetScanRequest) (*nmap_vulners_service.GetScanResponse, error) {
+	if len(req.GetScanId()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "scan_id is required")
+	}
+
+	scan, err := c.scans.Get(ctx, req.GetScanId())
+	if err != nil {
+		return nil, scanError(err)
+	}
+	err = checkScanScope(ctx, scan)
+	if err != nil {
+		return nil, err
+	}
+
+	return &nmap_vulners_service.GetScanResponse{Scan: scanToProto(scan)}, nil
+}
+
+func (c *GRPCController) ListScans(ctx context.Context, req *nmap_vulners_service.ListScansRequest) (*nmap_vulners_service.ListScansResponse, error) {
+	if req.GetLimit() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "limit cannot be negative")
+	}
+
+	filter := repository.ScanFilter{Target: req.GetTarget(), Limit: int(req.GetLimit())}
+	if req.GetSince() != nil {
+		filter.Since = req.GetSince().AsTime()
+	}
+	identity, scoped := auth.FromContext(ctx)
+	if scoped {
+		filter.Limit = 0 // scans out of the scope are dropped first
+	}
+
+	scans, err := c.scans.List(ctx, filter)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to list scans")
+	}
+	if scoped {
+		scans = slices.DeleteFunc(scans, func(scan entity.Scan) bool {
+			return len(identity.Forbidden(scanTargets(scan))) > 0
+		})
+		if req.GetLimit() > 0 && len(scans) > int(req.GetLimit()) {
+			scans = scans[:req.GetLimit()]
+		}
+	}
+
+	response := &nmap_vulners_service.ListScansResponse{Scans: make([]*nmap_vulners_service.Scan, len(scans))}
+	for i, scan := range scans {
+		if !req.GetIncludeResults() {
+			scan.Results = nil
+		}
+		response.Scans[i] = scanToProto(scan)
+	}
+
+	return response, nil
+}
+
+func (c *GRPCController) DiffScans(ctx context.Context, req *nmap_vulners_service.DiffScansRequest) (*nmap_vulners_service.DiffScansResponse, error) {
+	if len(req.GetOldScanId()) == 0 || len(req.GetNewScanId()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "old_scan_id and new_scan_id are required")
+	}
+	for _, id := range []string{req.GetOldScanId(), req.GetNewScanId()} {
+		scan, err := c.scans.Get(ctx, id)
+		if err != nil {
+			return nil, scanError(err)
+		}
+		err = checkScanScope(ctx, scan)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	diff, err := c.scans.Diff(ctx, req.GetOldScanId(), req.GetNewScanId())
+	if err != nil {
+		return nil, scanError(err)
+	}
+
+	return scanDiffToProto(diff), nil
+}
+
+func (c *GRPCController) ImportNmapXML(ctx context.Context, req *nmap_vulners_service.ImportNmapXMLRequest) (*nmap_vulners_service.ImportNmapXMLResponse, error) {
+	if len(req.GetXml()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "xml is required")
+	}
+
+	scan, err := c.scans.Import(ctx, req.GetXml())
 	if err != nil {
 		switch {
-		case errors.Is(err, service.ErrScanTimeout):
-			return nil, status.Error(codes.DeadlineExceeded, err.Error())
+		case errors.Is(err, service.ErrInvalidNmapXML), errors.Is(err, service.ErrParseOutput):
+			return nil, status.Error(codes.InvalidArgument, err.Error())
 		default:
-			return nil, status.Error(codes.Internal, "failed to check vulnerability")
+			return nil, status.Error(codes.Internal, "failed to import nmap xml")
 		}
 	}
 
-	results := make([]*nmap_vulners_service.TargetsResult, len(checkVulnResult))
+	return &nmap_vulners_service.ImportNmapXMLResponse{Scan: scanToProto(scan)}, nil
+}
+
+func (c *GRPCController) CancelScan(ctx context.Context, req *nmap_vulners_service.CancelScanRequest) (*nmap_vulners_service.CancelScanResponse, error) {
+	if len(req.GetScanId()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "scan_id is required")
+	}
+
+	scan, err := c.scans.Get(ctx, req.GetScanId())
+	if err != nil {
+		return nil, scanError(err)
+	}
+	err = checkScanScope(ctx, scan)
+	if err != nil {
+		return nil, err
+	}
+
+	scan, err = c.scans.Cancel(req.GetScanId())
+	if err != nil {
+		return nil, scanError(err)
+	}
+
+	return &nmap_vulners_service.CancelScanResponse{Scan: scanToProto(scan)}, nil
+}
+
+// parseCheckVulnRequest returns parsed targets, TCP ports and UDP ports of the request.
+func (c *GRPCController) parseCheckVulnRequest(ctx context.Context, req *nmap_vulners_service.CheckVulnRequest) ([]target.Target, []string, []string, error) {
+	values := req.GetTargets()
 
-	for i, host := range checkVulnResult {
-		target := &nmap_vulners_service.TargetsResult{
-			Target:   host.TargetIP,
-			Services: make([]*nmap_vulners_service.Service, len(host.Services)),
+	if len(values) == 0 {
+		return nil, nil, nil, status.Error(codes.InvalidArgument, "targets is required")
+	}
+	for _, value := range values {
+		if len(value) == 0 {
+			return nil, nil, nil, status.Error(codes.InvalidArgument, "target cannot be an empty string")
 		}
+	}
+
+	tcpPorts, err := c.ports.Parse(ports.TCP, append(portsToStrings(req.GetTcpPorts()), req.GetTcpPortRanges()...))
+	if err != nil {
+		return nil, nil, nil, portsError(err)
+	}
+	udpPorts, err := c.ports.Parse(ports.UDP, append(portsToStrings(req.GetUdpPorts()), req.GetUdpPortRanges()...))
+	if err != nil {
+		return nil, nil, nil, portsError(err)
+	}
+	if len(tcpPorts) == 0 && len(udpPorts) == 0 {
+		tcpPorts = c.ports.DefaultTCP()
+	}
 
-		for j, service := range host.Services {
-			tempService := &nmap_vulners_service.Service{
-				Name:    service.Name,
-				Version: service.Version,
-				TcpPort: int32(service.TcpPort),
-				Vulns:   make([]*nmap_vulners_service.Vulnerability, len(service.Vulns)),
+	targets, err := c.targets.ParseAll(ctx, values)
+	if err != nil {
+		switch {
+		case errors.Is(err, target.ErrInvalidTarget), errors.Is(err, target.ErrTooManyHosts):
+			return nil, nil, nil, status.Error(codes.InvalidArgument, err.Error())
+		case errors.Is(err, context.Canceled):
+			return nil, nil, nil, status.Error(codes.Canceled, err.Error())
+		default:
+			return nil, nil, nil, status.Error(codes.Internal, "failed to parse targets")
+		}
+	}
+
+	forbidden := c.scope.Forbidden(clientAddr(ctx), targets)
+	identity, ok := auth.FromContext(ctx)
+	if ok {
+		for _, value := range identity.Forbidden(targets) {
+			if !slices.Contains(forbidden, value) {
+				forbidden = append(forbidden, value)
 			}
+		}
+	}
+	if len(forbidden) > 0 {
+		return nil, nil, nil, status.Error(codes.PermissionDenied, "targets are out of allowed scope: "+strings.Join(forbidden, ", "))
+	}
+
+	return targets, tcpPorts, udpPorts, nil
+}
+
+// checkScanScope denies access to a scan if any of its targets or scanned hosts is out of the scope of
+// the caller roles, as the caller would not be allowed to run such a scan.
+func checkScanScope(ctx context.Context, scan entity.Scan) error {
+	identity, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	forbidden := identity.Forbidden(scanTargets(scan))
+	if len(forbidden) > 0 {
+		return status.Error(codes.PermissionDenied, "scan targets are out of allowed scope: "+strings.Join(forbidden, ", "))
+	}
+	return nil
+}
 
-			for k, vuln := range service.Vulns {
-				tempService.Vulns[k] = &nmap_vulners_service.Vulnerability{
-					Identifier: vuln.Identifier,
-					CvssScore:  vuln.CvssScore,
+// scanTargets returns targets of a stored scan with hostnames resolved to the addresses they were scanned by,
+// and hosts found by the scan, which are the only known targets of an imported one.
+func scanTargets(scan entity.Scan) []target.Target {
+	var targets []target.Target
+	for _, value := range scan.Targets {
+		parsed, err := target.Parse(value)
+		if err != nil {
+			parsed = target.Target{Value: value, Kind: target.KindHostname} // cannot be checked, so allowed only by unlimited roles
+		}
+		if parsed.Kind == target.KindHostname {
+			for _, address := range scan.Addresses[value] {
+				addr, err := netip.ParseAddr(address)
+				if err == nil {
+					parsed.Addresses = append(parsed.Addresses, addr)
 				}
 			}
-			target.Services[j] = tempService
 		}
-		results[i] = target
+		targets = append(targets, parsed)
 	}
+	for _, host := range scan.Results {
+		parsed, err := target.Parse(host.TargetIP)
+		if err == nil && parsed.Kind == target.KindIP {
+			targets = append(targets, parsed)
+		}
+	}
+	return targets
+}
 
-	return &nmap_vulners_service.CheckVulnResponse{Results: results}, nil
+func portsError(err error) error {
+	switch {
+	case errors.Is(err, ports.ErrInvalidPorts):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, ports.ErrPresetUnavailable):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		return status.Error(codes.Internal, "failed to parse ports")
+	}
+}
+
+// clientAddr returns the client IP address, it is invalid if the connection is not over IP.
+func clientAddr(ctx context.Context) netip.Addr {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return netip.Addr{}
+	}
+	addrPort, err := netip.ParseAddrPort(p.Addr.String())
+	if err != nil {
+		return netip.Addr{}
+	}
+	return addrPort.Addr()
+}
+
+func portsToStrings(ports []int32) []string {
+	converted := make([]string, len(ports))
+	for i := 0; i < len(ports); i++ {
+		converted[i] = strconv.Itoa(int(ports[i]))
+	}
+	return converted
+}
+
+func checkVulnError(err error) error {
+	switch {
+	case errors.Is(err, service.ErrScanTimeout), errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, service.ErrPrivilegesRequired):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		return status.Error(codes.Internal, "failed to check vulnerability")
+	}
+}
+
+func scanError(err error) error {
+	switch {
+	case errors.Is(err, service.ErrScanNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, service.ErrScanFinished), errors.Is(err, service.ErrScanNotDone):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		return status.Error(codes.Internal, "failed to get scan")
+	}
 }
@@ -0,0 +1,144 @@
+package enrich
+
+import (
+	"compress/gzip"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/NikolaB131/nmap-vulners-service/internal/entity"
+)
+
+// EPSS adds Exploit Prediction Scoring System probability and percentile (https://www.first.org/epss/)
+// from a daily CSV snapshot, plain or gzipped.
+type EPSS struct {
+	log  *slog.Logger
+	path string
+
+	mu     sync.RWMutex
+	scores map[string]epssScore // keyed by CVE ID
+}
+
+type epssScore struct {
+	probability float32
+	percentile  float32
+}
+
+func NewEPSS(logger *slog.Logger, path string) (*EPSS, error) {
+	epss := &EPSS{log: logger, path: path}
+	err := epss.Reload()
+	if err != nil {
+		return nil, err
+	}
+	return epss, nil
+}
+
+// Reload reads the snapshot file again, the previous snapshot is kept if it cannot be read.
+func (e *EPSS) Reload() error {
+	scores, model, err := readEPSS(e.path)
+	if err != nil {
+		return fmt.Errorf("unable to read epss snapshot: %w", err)
+	}
+
+	e.mu.Lock()
+	e.scores = scores
+	e.mu.Unlock()
+
+	e.log.Info("EPSS snapshot loaded", slog.String("model", model), slog.Int("cves", len(scores)))
+	return nil
+}
+
+func (e *EPSS) Enrich(vulns []entity.Vulnerability) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	for i := range vulns {
+		score, ok := e.scores[cveID(vulns[i].Identifier)]
+		if ok {
+			vulns[i].EPSS = score.probability
+			vulns[i].EPSSPercentile = score.percentile
+		}
+	}
+}
+
+// readEPSS parses a snapshot like
+//
+//	#model_version:v2023.03.01,score_date:2024-05-01T00:00:00+0000
+//	cve,epss,percentile
+//	CVE-1999-0001,0.01141,0.83794
+//
+// and returns the scores with the model comment line.
+func readEPSS(path string) (map[string]epssScore, string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, "", err
+	}
+	defer file.Close()
+
+	var reader io.Reader = file
+	if strings.HasSuffix(path, ".gz") {
+		gzipReader, err := gzip.NewReader(file)
+		if err != nil {
+			return nil, "", err
+		}
+		defer gzipReader.Close()
+		reader = gzipReader
+	}
+
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1 // the model comment line has a different number of fields
+	csvReader.ReuseRecord = true
+
+	scores := make(map[string]epssScore)
+	var model string
+	columns := map[string]int{}
+	for line := 1; ; line++ {
+		record, err := csvReader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, "", err
+		}
+
+		switch {
+		case strings.HasPrefix(record[0], "#"):
+			model = strings.TrimPrefix(strings.Join(record, ","), "#")
+			continue
+		case len(columns) == 0:
+			for i, column := range record {
+				columns[column] = i
+			}
+			for _, column := range []string{"cve", "epss", "percentile"} {
+				if _, ok := columns[column]; !ok {
+					return nil, "", fmt.Errorf("column %q not found", column)
+				}
+			}
+			continue
+		}
+
+		if len(record) != len(columns) {
+			return nil, "", fmt.Errorf("line %d: expected %d fields, got %d", line, len(columns), len(record))
+		}
+		probability, err := strconv.ParseFloat(record[columns["epss"]], 32)
+		if err != nil {
+			return nil, "", fmt.Errorf("line %d: invalid epss: %w", line, err)
+		}
+		percentile, err := strconv.ParseFloat(record[columns["percentile"]], 32)
+		if err != nil {
+			return nil, "", fmt.Errorf("line %d: invalid percentile: %w", line, err)
+		}
+		scores[record[columns["cve"]]] = epssScore{probability: float32(probability), percentile: float32(percentile)}
+	}
+
+	if len(columns) == 0 {
+		return nil, "", errors.New("header not found")
+	}
+	return scores, model, nil
+}
@@ -0,0 +1,29 @@
+package enrich_test
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NikolaB131/nmap-vulners-service/internal/enrich"
+	"github.com/NikolaB131/nmap-vulners-service/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEPSS(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "epss.csv")
+	require.NoError(t, os.WriteFile(path, []byte("#model_version:v2023.03.01,score_date:2024-05-01T00:00:00+0000\ncve,epss,percentile\nCVE-2017-7529,0.03210,0.91112\n"), 0o644))
+
+	epss, err := enrich.NewEPSS(slog.Default(), path)
+	require.NoError(t, err)
+	vulns := []entity.Vulnerability{{Identifier: "NGINX:CVE-2017-7529"}, {Identifier: "SSV:96273"}}
+	epss.Enrich(vulns)
+	assert.InDelta(t, 0.0321, vulns[0].EPSS, 1e-6)
+	assert.InDelta(t, 0.91112, vulns[0].EPSSPercentile, 1e-6)
+	assert.Zero(t, vulns[1].EPSS)
+
+	require.NoError(t, os.WriteFile(path, []byte("cve,epss,percentile\nCVE-2017-7529,high,0.9\n"), 0o644))
+	assert.Error(t, epss.Reload())
+}
@@ -0,0 +1,90 @@
+// Package enrich annotates found vulnerabilities with data from local catalogs, like CISA KEV.
+package enrich
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/NikolaB131/nmap-vulners-service/internal/entity"
+)
+
+// cveRegexp finds CVE ID in identifiers like "CVE-2017-7529" and "NGINX:CVE-2017-7529".
+var cveRegexp = regexp.MustCompile(`CVE-\d{4}-\d{4,}`)
+
+func cveID(identifier string) string {
+	return cveRegexp.FindString(identifier)
+}
+
+// KEV marks vulnerabilities listed in CISA Known Exploited Vulnerabilities catalog
+// (https://www.cisa.gov/known-exploited-vulnerabilities-catalog), read from a local JSON file.
+type KEV struct {
+	log  *slog.Logger
+	path string
+
+	mu        sync.RWMutex
+	dateAdded map[string]time.Time // keyed by CVE ID
+}
+
+type kevCatalog struct {
+	CatalogVersion  string `json:"catalogVersion"`
+	Vulnerabilities []struct {
+		CveID     string `json:"cveID"`
+		DateAdded string `json:"dateAdded"`
+	} `json:"vulnerabilities"`
+}
+
+func NewKEV(logger *slog.Logger, path string) (*KEV, error) {
+	kev := &KEV{log: logger, path: path}
+	err := kev.Reload()
+	if err != nil {
+		return nil, err
+	}
+	return kev, nil
+}
+
+// Reload reads the catalog file again, the previous catalog is kept if it cannot be read.
+func (k *KEV) Reload() error {
+	data, err := os.ReadFile(k.path)
+	if err != nil {
+		return fmt.Errorf("unable to read kev catalog: %w", err)
+	}
+	var catalog kevCatalog
+	err = json.Unmarshal(data, &catalog)
+	if err != nil {
+		return fmt.Errorf("unable to decode kev catalog: %w", err)
+	}
+
+	dateAdded := make(map[string]time.Time, len(catalog.Vulnerabilities))
+	for _, vuln := range catalog.Vulnerabilities {
+		date, err := time.Parse(time.DateOnly, vuln.DateAdded)
+		if err != nil {
+			return fmt.Errorf("kev catalog: %s has invalid dateAdded: %w", vuln.CveID, err)
+		}
+		dateAdded[vuln.CveID] = date
+	}
+
+	k.mu.Lock()
+	k.dateAdded = dateAdded
+	k.mu.Unlock()
+
+	k.log.Info("KEV catalog loaded", slog.String("version", catalog.CatalogVersion), slog.Int("cves", len(dateAdded)))
+	return nil
+}
+
+func (k *KEV) Enrich(vulns []entity.Vulnerability) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	for i := range vulns {
+		dateAdded, ok := k.dateAdded[cveID(vulns[i].Identifier)]
+		if ok {
+			vulns[i].InKEV = true
+			vulns[i].KEVDateAdded = dateAdded
+		}
+	}
+}
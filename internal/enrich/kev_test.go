@@ -0,0 +1,36 @@
+package enrich_test
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NikolaB131/nmap-vulners-service/internal/enrich"
+	"github.com/NikolaB131/nmap-vulners-service/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKEVReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kev.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"vulnerabilities": []}`), 0o644))
+
+	kev, err := enrich.NewKEV(slog.Default(), path)
+	require.NoError(t, err)
+	vulns := []entity.Vulnerability{{Identifier: "CVE-2018-10933"}}
+	kev.Enrich(vulns)
+	assert.False(t, vulns[0].InKEV)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"vulnerabilities": [{"cveID": "CVE-2018-10933", "dateAdded": "2022-03-25"}]}`), 0o644))
+	require.NoError(t, kev.Reload())
+	kev.Enrich(vulns)
+	assert.True(t, vulns[0].InKEV)
+
+	// A broken catalog does not replace the loaded one
+	require.NoError(t, os.WriteFile(path, []byte(`{`), 0o644))
+	assert.Error(t, kev.Reload())
+	vulns = []entity.Vulnerability{{Identifier: "CVE-2018-10933"}}
+	kev.Enrich(vulns)
+	assert.True(t, vulns[0].InKEV)
+}
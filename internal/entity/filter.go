@@ -0,0 +1,79 @@
+package entity
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+type VulnsSort string
+
+const (
+	VulnsSortNone VulnsSort = ""     // keep the order vulnerabilities were found in
+	VulnsSortCVSS VulnsSort = "cvss" // highest CVSS score first
+	VulnsSortEPSS VulnsSort = "epss" // highest EPSS probability first
+)
+
+// VulnsFilter selects and orders vulnerabilities reported by a scan, zero value keeps all of them as found.
+type VulnsFilter struct {
+	MinCVSS       float32   `json:"min_cvss"`
+	OnlyExploits  bool      `json:"only_exploits"`
+	IncludeTypes  []string  `json:"include_types"` // vulners bulletin types, empty means all
+	ExcludeTypes  []string  `json:"exclude_types"`
+	OnlyKEV       bool      `json:"only_kev"`
+	MinEPSS       float32   `json:"min_epss"`
+	Sort          VulnsSort `json:"sort"`
+	MaxPerService int       `json:"max_per_service"` // applied after sorting, 0 means no limit
+}
+
+func (f VulnsFilter) Match(vuln Vulnerability) bool {
+	// Same as vulners.nse mincvss, exploits often have no score and are kept anyway
+	if vuln.CvssScore < f.MinCVSS && !(vuln.CvssScore == 0 && vuln.IsExploit) {
+		return false
+	}
+	if f.OnlyExploits && !vuln.IsExploit {
+		return false
+	}
+	if len(f.IncludeTypes) > 0 && !containsFold(f.IncludeTypes, vuln.Type) {
+		return false
+	}
+	if containsFold(f.ExcludeTypes, vuln.Type) {
+		return false
+	}
+	if f.OnlyKEV && !vuln.InKEV {
+		return false
+	}
+	if vuln.EPSS < f.MinEPSS {
+		return false
+	}
+	return true
+}
+
+// Apply removes vulnerabilities not matching the filter and sorts the rest in place.
+func (f VulnsFilter) Apply(vulns []Vulnerability) []Vulnerability {
+	vulns = slices.DeleteFunc(vulns, func(vuln Vulnerability) bool {
+		return !f.Match(vuln)
+	})
+
+	switch f.Sort {
+	case VulnsSortCVSS:
+		slices.SortStableFunc(vulns, func(a, b Vulnerability) int {
+			return cmp.Compare(b.CvssScore, a.CvssScore)
+		})
+	case VulnsSortEPSS:
+		slices.SortStableFunc(vulns, func(a, b Vulnerability) int {
+			return cmp.Compare(b.EPSS, a.EPSS)
+		})
+	}
+
+	if f.MaxPerService > 0 && len(vulns) > f.MaxPerService {
+		vulns = vulns[:f.MaxPerService]
+	}
+	return vulns
+}
+
+func containsFold(values []string, value string) bool {
+	return slices.ContainsFunc(values, func(v string) bool {
+		return strings.EqualFold(v, value)
+	})
+}
@@ -1,20 +1,75 @@
 package entity
 
+import "time"
+
+// Vulnerabilities lookup status of a port
+const (
+	ScriptStatusFound    = "found"     // vulnerabilities were found
+	ScriptStatusNotFound = "not_found" // lookup was done, nothing was found
+	ScriptStatusSkipped  = "skipped"   // port is not open or service version is unknown, lookup is not possible
+	ScriptStatusError    = "error"     // lookup failed
+)
+
+const PortStateOpen = "open"
+
 type (
 	HostResult struct {
-		TargetIP string
-		Services []Service
+		TargetIP  string         `json:"target_ip"`
+		Status    string         `json:"status"` // up or down
+		Hostnames []string       `json:"hostnames"`
+		OS        OSGuess        `json:"os"`
+		Services  []Service      `json:"services"` // every scanned port, whatever its state
+		Warnings  []ParseWarning `json:"warnings"`
+		Error     string         `json:"error"` // set if the target could not be scanned, TargetIP is the target as given then
+	}
+
+	// OSGuess is the most accurate nmap OS detection match, empty if OS detection was not run.
+	OSGuess struct {
+		Name     string `json:"name"`
+		Accuracy int    `json:"accuracy"` // percent
 	}
 
 	Service struct {
-		Name    string
-		Version string
-		TcpPort uint16
-		Vulns   []Vulnerability
+		Name         string          `json:"name"`
+		Product      string          `json:"product"`
+		Version      string          `json:"version"`
+		ExtraInfo    string          `json:"extra_info"`
+		TcpPort      uint16          `json:"tcp_port"` // port number, UDP ones too, the name is kept for stored scans
+		Protocol     string          `json:"protocol"` // tcp, udp or sctp
+		State        string          `json:"state"`    // open, closed, filtered...
+		CPEs         []string        `json:"cpes"`
+		ScriptStatus string          `json:"script_status"`
+		Vulns        []Vulnerability `json:"vulns"`
 	}
 
 	Vulnerability struct {
-		Identifier string
-		CvssScore  float32
+		Identifier string  `json:"identifier"`
+		CvssScore  float32 `json:"cvss_score"`
+		Type       string  `json:"type"` // vulners bulletin type, e.g. cve, githubexploit, packetstorm
+		IsExploit  bool    `json:"is_exploit"`
+		URL        string  `json:"url"`
+		CPE        string  `json:"cpe"` // CPE the vulnerability was found for
+
+		InKEV        bool      `json:"in_kev"`         // listed in CISA Known Exploited Vulnerabilities catalog
+		KEVDateAdded time.Time `json:"kev_date_added"` // when it was added to the catalog
+
+		EPSS           float32 `json:"epss"` // probability of exploitation in the next 30 days, 0 if unknown
+		EPSSPercentile float32 `json:"epss_percentile"`
+	}
+
+	// ParseWarning describes a malformed vulners script entry that was skipped.
+	ParseWarning struct {
+		TcpPort    uint16 `json:"tcp_port"`
+		Protocol   string `json:"protocol"` // tcp or udp, TcpPort is the port number of either
+		CPE        string `json:"cpe"`
+		Identifier string `json:"identifier"`
+		Field      string `json:"field"`
+		Value      string `json:"value"`
+		Message    string `json:"message"`
 	}
 )
+
+// Open tells whether the port is open. Scans saved before port states were recorded have only open ports.
+func (s Service) Open() bool {
+	return s.State == "" || s.State == PortStateOpen
+}
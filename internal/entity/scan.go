@@ -0,0 +1,64 @@
+package entity
+
+import "time"
+
+type ScanStatus string
+
+const (
+	ScanStatusQueued    ScanStatus = "queued"
+	ScanStatusRunning   ScanStatus = "running"
+	ScanStatusDone      ScanStatus = "done"
+	ScanStatusFailed    ScanStatus = "failed"
+	ScanStatusCancelled ScanStatus = "cancelled"
+)
+
+type Scan struct {
+	ID         string              `json:"id"`
+	Status     ScanStatus          `json:"status"`
+	Targets    []string            `json:"targets"`
+	Addresses  map[string][]string `json:"addresses"` // addresses hostnames of Targets were checked against the scope with, nmap scans them instead of resolving hostnames again
+	TcpPorts   []string            `json:"tcp_ports"`
+	UdpPorts   []string            `json:"udp_ports"`
+	CreatedAt  time.Time           `json:"created_at"`
+	StartedAt  time.Time           `json:"started_at"`
+	FinishedAt time.Time           `json:"finished_at"`
+	Error      string              `json:"error"`
+	Results    []HostResult        `json:"results"` // partial while the scan is running
+	Stats      ScanStats           `json:"stats"`
+	Filter     VulnsFilter         `json:"filter"`
+}
+
+type ScanStats struct {
+	Elapsed    time.Duration `json:"elapsed"`
+	HostsUp    int           `json:"hosts_up"`
+	HostsDown  int           `json:"hosts_down"`
+	HostsTotal int           `json:"hosts_total"`
+}
+
+func (s ScanStatus) Finished() bool {
+	return s == ScanStatusDone || s == ScanStatusFailed || s == ScanStatusCancelled
+}
+
+// ScanDiff lists what changed between two scans, unchanged hosts and services are omitted.
+type ScanDiff struct {
+	OldScanID string
+	NewScanID string
+	Hosts     []HostDiff
+}
+
+type HostDiff struct {
+	TargetIP    string
+	OpenedPorts []Service // services found only in the new scan
+	ClosedPorts []Service // services found only in the old scan
+	Services    []ServiceDiff
+}
+
+type ServiceDiff struct {
+	Protocol      string
+	TcpPort       uint16
+	Name          string
+	OldVersion    string
+	NewVersion    string // differs from OldVersion only if the version changed
+	NewVulns      []Vulnerability
+	ResolvedVulns []Vulnerability
+}
@@ -0,0 +1,293 @@
+package lookup
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/NikolaB131/nmap-vulners-service/internal/entity"
+	"github.com/NikolaB131/nmap-vulners-service/pkg/sl"
+	"golang.org/x/sync/singleflight"
+)
+
+// Cache keeps search results of another source for ttl, so the same CPEs found on many hosts
+// and in repeated scans are searched once. At most maxEntries results are kept, least recently used are evicted first.
+// If dir is not empty, results are also stored there as JSON files and loaded back on start.
+// Failed searches are not cached.
+type Cache struct {
+	log        *slog.Logger
+	source     Source
+	ttl        time.Duration
+	maxEntries int
+	dir        string
+	group      singleflight.Group
+
+	mu      sync.Mutex
+	entries map[string]*list.Element // values are *cacheEntry
+	order   *list.List               // most recently used first
+	pending []diskChange             // changes not yet written to dir, in the order they were made
+	hits    uint64
+	misses  uint64
+
+	diskMu sync.Mutex // held while pending changes are written, so they are written in order
+}
+
+type cacheEntry struct {
+	Key       string                 `json:"key"`
+	Vulns     []entity.Vulnerability `json:"vulns"`
+	ExpiresAt time.Time              `json:"expires_at"`
+}
+
+// diskChange is an entry to save or, if entry is nil, a key whose file must be removed
+type diskChange struct {
+	key   string
+	entry *cacheEntry
+}
+
+func NewCache(logger *slog.Logger, source Source, ttl time.Duration, maxEntries int, dir string) (*Cache, error) {
+	c := &Cache{
+		log:        logger,
+		source:     source,
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		dir:        dir,
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+	}
+	if dir == "" {
+		return c, nil
+	}
+
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create cache directory: %w", err)
+	}
+	err = c.load()
+	if err != nil {
+		return nil, err
+	}
+	c.flush()
+	logger.Info("CPE cache loaded from disk", slog.String("path", dir), slog.Int("entries", len(c.entries)))
+	return c, nil
+}
+
+func (c *Cache) Search(ctx context.Context, query Query) ([]entity.Vulnerability, error) {
+	key := cacheKey(query)
+
+	c.mu.Lock()
+	vulns, ok := c.get(key)
+	if ok {
+		c.hits++
+	} else {
+		c.misses++
+	}
+	hits, misses := c.hits, c.misses
+	c.mu.Unlock()
+
+	c.log.Debug(
+		"CPE cache lookup",
+		slog.String("key", key),
+		slog.Bool("hit", ok),
+		slog.Uint64("hits", hits),
+		slog.Uint64("misses", misses),
+	)
+	if ok {
+		return vulns, nil
+	}
+	c.flush() // an expired entry may have been removed
+
+	// Concurrent scans finding the same CPE wait for a single search. It is not cancelled when the scan which started it is,
+	// as other scans may still wait for it, sources limit the time of their requests themselves
+	results := c.group.DoChan(key, func() (any, error) {
+		vulns, err := c.source.Search(context.WithoutCancel(ctx), query)
+		if err != nil {
+			return nil, err
+		}
+		c.put(key, vulns)
+		return vulns, nil
+	})
+	select {
+	case result := <-results:
+		if result.Err != nil {
+			return nil, result.Err
+		}
+		return slices.Clone(result.Val.([]entity.Vulnerability)), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// LogStats logs hits and misses since the previous summary every interval, until ctx is done.
+// Nothing is logged if there were no lookups.
+func (c *Cache) LogStats(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var loggedHits, loggedMisses uint64
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		c.mu.Lock()
+		hits, misses, entries := c.hits, c.misses, len(c.entries)
+		c.mu.Unlock()
+		if hits == loggedHits && misses == loggedMisses {
+			continue
+		}
+
+		c.log.Info(
+			"CPE cache stats",
+			slog.Uint64("hits", hits-loggedHits),
+			slog.Uint64("misses", misses-loggedMisses),
+			slog.Uint64("total_hits", hits),
+			slog.Uint64("total_misses", misses),
+			slog.Int("entries", entries),
+		)
+		loggedHits, loggedMisses = hits, misses
+	}
+}
+
+// get must be called with Cache.mu held.
+func (c *Cache) get(key string) ([]entity.Vulnerability, bool) {
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := element.Value.(*cacheEntry)
+	if time.Now().After(entry.ExpiresAt) {
+		c.remove(element)
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+	return slices.Clone(entry.Vulns), true
+}
+
+func (c *Cache) put(key string, vulns []entity.Vulnerability) {
+	entry := &cacheEntry{Key: key, Vulns: vulns, ExpiresAt: time.Now().Add(c.ttl)}
+
+	c.mu.Lock()
+	c.add(entry)
+	if c.dir != "" {
+		c.pending = append(c.pending, diskChange{key: key, entry: entry})
+	}
+	c.mu.Unlock()
+
+	c.flush()
+}
+
+// add must be called with Cache.mu held.
+func (c *Cache) add(entry *cacheEntry) {
+	if element, ok := c.entries[entry.Key]; ok {
+		element.Value = entry
+		c.order.MoveToFront(element)
+		return
+	}
+	c.entries[entry.Key] = c.order.PushFront(entry)
+	for len(c.entries) > c.maxEntries {
+		c.remove(c.order.Back())
+	}
+}
+
+// remove must be called with Cache.mu held.
+func (c *Cache) remove(element *list.Element) {
+	entry := c.order.Remove(element).(*cacheEntry)
+	delete(c.entries, entry.Key)
+	if c.dir != "" {
+		c.pending = append(c.pending, diskChange{key: entry.Key})
+	}
+}
+
+// flush writes pending changes to dir. Files are written without Cache.mu held, but in the order
+// the entries were changed, so an entry evicted right after it was added never stays on disk.
+func (c *Cache) flush() {
+	c.diskMu.Lock()
+	defer c.diskMu.Unlock()
+
+	c.mu.Lock()
+	changes := c.pending
+	c.pending = nil
+	c.mu.Unlock()
+
+	for _, change := range changes {
+		if change.entry != nil {
+			err := c.save(change.entry)
+			if err != nil {
+				c.log.Error("unable to save CPE cache entry", slog.String("key", change.key), sl.Err(err))
+			}
+			continue
+		}
+		err := os.Remove(c.path(change.key))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			c.log.Error("unable to remove CPE cache entry", slog.String("key", change.key), sl.Err(err))
+		}
+	}
+}
+
+func (c *Cache) save(entry *cacheEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	// Write to a temporary file first, so a crash never leaves a half written entry behind
+	tmpPath := c.path(entry.Key) + ".tmp"
+	err = os.WriteFile(tmpPath, data, 0o644)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, c.path(entry.Key))
+}
+
+// load reads entries stored by previous runs, expired and unreadable ones are removed.
+func (c *Cache) load() error {
+	paths, err := filepath.Glob(filepath.Join(c.dir, "*.json"))
+	if err != nil {
+		return err
+	}
+
+	var entries []*cacheEntry
+	now := time.Now()
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("unable to read cache entry: %w", err)
+		}
+		var entry cacheEntry
+		err = json.Unmarshal(data, &entry)
+		if err != nil || now.After(entry.ExpiresAt) || c.path(entry.Key) != path {
+			os.Remove(path)
+			continue
+		}
+		entries = append(entries, &entry)
+	}
+
+	// The ones expiring last are the most recently added, they are kept if there are too many entries
+	slices.SortFunc(entries, func(a, b *cacheEntry) int {
+		return a.ExpiresAt.Compare(b.ExpiresAt)
+	})
+	for _, entry := range entries {
+		c.add(entry)
+	}
+	return nil
+}
+
+func (c *Cache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+func cacheKey(query Query) string {
+	return string(query.Type) + "|" + query.Software + "|" + query.Version
+}
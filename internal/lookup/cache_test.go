@@ -0,0 +1,193 @@
+package lookup_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/NikolaB131/nmap-vulners-service/internal/entity"
+	"github.com/NikolaB131/nmap-vulners-service/internal/lookup"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingSource answers every query with the same vulnerability and counts searches
+type countingSource struct {
+	searches atomic.Int32
+	release  chan struct{} // if not nil, searches wait until it is closed
+}
+
+func (s *countingSource) Search(ctx context.Context, query lookup.Query) ([]entity.Vulnerability, error) {
+	s.searches.Add(1)
+	if s.release != nil {
+		select {
+		case <-s.release:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return []entity.Vulnerability{{Identifier: "CVE-2018-15473", CvssScore: 5, Type: "cve"}}, nil
+}
+
+var opensshQuery = lookup.Query{Software: "cpe:/a:openbsd:openssh:7.4", Version: "7.4", Type: lookup.QueryTypeCPE}
+
+func TestCacheHit(t *testing.T) {
+	source := &countingSource{}
+	cache, err := lookup.NewCache(slog.Default(), source, time.Hour, 10, "")
+	require.NoError(t, err)
+
+	for range 3 {
+		vulns, err := cache.Search(context.Background(), opensshQuery)
+		require.NoError(t, err)
+		assert.Equal(t, "CVE-2018-15473", vulns[0].Identifier)
+	}
+	assert.Equal(t, int32(1), source.searches.Load())
+
+	_, err = cache.Search(context.Background(), lookup.Query{Software: "cpe:/a:openbsd:openssh:7.5", Version: "7.5", Type: lookup.QueryTypeCPE})
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), source.searches.Load())
+}
+
+func TestCacheTTL(t *testing.T) {
+	source := &countingSource{}
+	cache, err := lookup.NewCache(slog.Default(), source, 50*time.Millisecond, 10, "")
+	require.NoError(t, err)
+
+	_, err = cache.Search(context.Background(), opensshQuery)
+	require.NoError(t, err)
+	time.Sleep(100 * time.Millisecond)
+	_, err = cache.Search(context.Background(), opensshQuery)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), source.searches.Load())
+}
+
+func TestCacheMaxEntries(t *testing.T) {
+	source := &countingSource{}
+	cache, err := lookup.NewCache(slog.Default(), source, time.Hour, 1, "")
+	require.NoError(t, err)
+
+	other := lookup.Query{Software: "cpe:/a:libssh:libssh:0.8.1", Version: "0.8.1", Type: lookup.QueryTypeCPE}
+	for _, query := range []lookup.Query{opensshQuery, other, opensshQuery} {
+		_, err = cache.Search(context.Background(), query)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(3), source.searches.Load(), "the first entry must be evicted by the second one")
+}
+
+func TestCacheDiskStore(t *testing.T) {
+	source := &countingSource{}
+	dir := t.TempDir()
+	cache, err := lookup.NewCache(slog.Default(), source, time.Hour, 10, dir)
+	require.NoError(t, err)
+	_, err = cache.Search(context.Background(), opensshQuery)
+	require.NoError(t, err)
+
+	// A new cache over the same directory imitates a restart
+	restarted, err := lookup.NewCache(slog.Default(), source, time.Hour, 10, dir)
+	require.NoError(t, err)
+	vulns, err := restarted.Search(context.Background(), opensshQuery)
+	require.NoError(t, err)
+	assert.Equal(t, "CVE-2018-15473", vulns[0].Identifier)
+	assert.Equal(t, int32(1), source.searches.Load())
+}
+
+func TestCacheDiskStoreEviction(t *testing.T) {
+	source := &countingSource{}
+	dir := t.TempDir()
+	cache, err := lookup.NewCache(slog.Default(), source, time.Hour, 2, dir)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			version := fmt.Sprintf("7.%d", i)
+			_, err := cache.Search(context.Background(), lookup.Query{Software: "cpe:/a:openbsd:openssh:" + version, Version: version, Type: lookup.QueryTypeCPE})
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	files, err := filepath.Glob(filepath.Join(dir, "*"))
+	require.NoError(t, err)
+	assert.Len(t, files, 2, "files of evicted entries must be removed")
+}
+
+func TestCacheSharedSearchOutlivesCaller(t *testing.T) {
+	source := &countingSource{release: make(chan struct{})}
+	cache, err := lookup.NewCache(slog.Default(), source, time.Hour, 10, "")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	firstErr := make(chan error, 1)
+	go func() {
+		_, err := cache.Search(ctx, opensshQuery)
+		firstErr <- err
+	}()
+	assert.Eventually(t, func() bool { return source.searches.Load() == 1 }, time.Second, 5*time.Millisecond)
+
+	secondVulns := make(chan []entity.Vulnerability, 1)
+	go func() {
+		vulns, err := cache.Search(context.Background(), opensshQuery)
+		assert.NoError(t, err)
+		secondVulns <- vulns
+	}()
+	// The second caller joins the search started by the first one
+	time.Sleep(50 * time.Millisecond)
+
+	cancel()
+	assert.ErrorIs(t, <-firstErr, context.Canceled, "the caller which started the search stops waiting for it")
+
+	close(source.release)
+	vulns := <-secondVulns
+	require.Len(t, vulns, 1, "the search is not cancelled with the context of the caller which started it")
+	assert.Equal(t, "CVE-2018-15473", vulns[0].Identifier)
+	assert.Equal(t, int32(1), source.searches.Load())
+}
+
+func TestCacheLogStats(t *testing.T) {
+	source := &countingSource{}
+	var logs lockedBuffer
+	cache, err := lookup.NewCache(slog.New(slog.NewTextHandler(&logs, nil)), source, time.Hour, 10, "")
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go cache.LogStats(ctx, 20*time.Millisecond)
+
+	for range 3 {
+		_, err = cache.Search(context.Background(), opensshQuery)
+		require.NoError(t, err)
+	}
+	assert.Eventually(t, func() bool {
+		return strings.Contains(logs.String(), `msg="CPE cache stats" hits=2 misses=1 total_hits=2 total_misses=1 entries=1`)
+	}, time.Second, 10*time.Millisecond)
+
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, 1, strings.Count(logs.String(), "CPE cache stats"), "nothing is logged without lookups")
+}
+
+// lockedBuffer is a bytes.Buffer safe for concurrent use by a logger and a test
+type lockedBuffer struct {
+	mu     sync.Mutex
+	buffer bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buffer.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buffer.String()
+}
@@ -0,0 +1,148 @@
+// Package lookup finds known vulnerabilities of a detected service by its CPEs in Go,
+// following the same heuristics as vulners.nse does inside nmap.
+package lookup
+
+import (
+	"cmp"
+	"context"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/NikolaB131/nmap-vulners-service/internal/entity"
+)
+
+type QueryType string
+
+const (
+	QueryTypeCPE      QueryType = "cpe"
+	QueryTypeSoftware QueryType = "software"
+)
+
+// Query is a single search, Software is a CPE or a product name depending on Type.
+type Query struct {
+	Software string
+	Version  string
+	Type     QueryType
+}
+
+// Source searches vulnerabilities of a software version. Nothing found is not an error.
+// Only Identifier, CvssScore, Type and IsExploit of the returned vulnerabilities are expected to be set.
+type Source interface {
+	Search(ctx context.Context, query Query) ([]entity.Vulnerability, error)
+}
+
+// Match holds vulnerabilities found for one key: a CPE, or "product version" for a software name search.
+// Vulnerabilities are sorted by CVSS score, highest first.
+type Match struct {
+	Key   string
+	Vulns []entity.Vulnerability
+}
+
+// cpeVersionRegexp splits the last CPE part into the numeric version and the rest (patch), like "1.2.3" and "p1" of "1.2.3p1".
+var cpeVersionRegexp = regexp.MustCompile(`:([\d.\-_]+)([^:]*)$`)
+
+type Resolver struct {
+	log    *slog.Logger
+	source Source
+}
+
+func NewResolver(logger *slog.Logger, source Source) *Resolver {
+	return &Resolver{log: logger, source: source}
+}
+
+// Resolve searches vulnerabilities for every CPE of a service. When nothing is found by CPEs,
+// product and version are searched as a software name.
+func (r *Resolver) Resolve(ctx context.Context, product string, version string, cpes []string) ([]Match, error) {
+	var matches []Match
+	seen := make(map[string]bool)
+
+	for _, cpe := range cpes {
+		// There are two CPEs for nginx, both of them have to be checked
+		cpe = strings.ReplaceAll(cpe, ":nginx:nginx", ":igor_sysoev:nginx")
+		vulns, err := r.searchCPE(ctx, cpe)
+		if err != nil {
+			return nil, err
+		}
+		if strings.Contains(cpe, ":igor_sysoev:nginx") {
+			cpe = strings.ReplaceAll(cpe, ":igor_sysoev:nginx", ":nginx:nginx")
+			nginxVulns, err := r.searchCPE(ctx, cpe)
+			if err != nil {
+				return nil, err
+			}
+			vulns = mergeByCVSS(vulns, nginxVulns)
+		}
+		if len(vulns) > 0 && !seen[cpe] {
+			seen[cpe] = true
+			matches = append(matches, Match{Key: cpe, Vulns: vulns})
+		}
+	}
+
+	if len(matches) == 0 && product != "" && version != "" {
+		vulns, err := r.search(ctx, Query{Software: product, Version: version, Type: QueryTypeSoftware})
+		if err != nil {
+			return nil, err
+		}
+		if len(vulns) > 0 {
+			matches = append(matches, Match{Key: product + " " + version, Vulns: vulns})
+		}
+	}
+
+	return matches, nil
+}
+
+// searchCPE searches by the numeric version taken from the CPE. If nothing is found and the version has a patch part
+// (like "7.4p1"), the patch is separated into its own CPE part and the search is repeated.
+func (r *Resolver) searchCPE(ctx context.Context, cpe string) ([]entity.Vulnerability, error) {
+	parts := cpeVersionRegexp.FindStringSubmatch(cpe)
+	if parts == nil {
+		return nil, nil
+	}
+	version, patch := parts[1], parts[2]
+
+	vulns, err := r.search(ctx, Query{Software: cpe, Version: version, Type: QueryTypeCPE})
+	if err != nil || len(vulns) > 0 || patch == "" {
+		return vulns, err
+	}
+
+	patchedCPE := cpeVersionRegexp.ReplaceAllString(cpe, ":${1}:${2}")
+	r.log.Debug("retrying vulnerabilities search with separated patch", slog.String("cpe", cpe), slog.String("new_cpe", patchedCPE))
+	return r.search(ctx, Query{Software: patchedCPE, Version: version, Type: QueryTypeCPE})
+}
+
+func (r *Resolver) search(ctx context.Context, query Query) ([]entity.Vulnerability, error) {
+	vulns, err := r.source.Search(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	r.log.Debug(
+		"vulnerabilities search done",
+		slog.String("software", query.Software),
+		slog.String("version", query.Version),
+		slog.String("type", string(query.Type)),
+		slog.Int("found", len(vulns)),
+	)
+
+	slices.SortFunc(vulns, func(a, b entity.Vulnerability) int {
+		if c := cmp.Compare(b.CvssScore, a.CvssScore); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.Identifier, a.Identifier)
+	})
+	return vulns, nil
+}
+
+// mergeByCVSS inserts vulnerabilities of first into second keeping the order by CVSS score.
+// Duplicates are kept, they are removed when the results are parsed.
+func mergeByCVSS(first []entity.Vulnerability, second []entity.Vulnerability) []entity.Vulnerability {
+	merged := slices.Clone(second)
+	pos := 0
+	for _, vuln := range first {
+		for pos < len(merged) && merged[pos].CvssScore >= vuln.CvssScore {
+			pos++
+		}
+		merged = slices.Insert(merged, pos, vuln)
+	}
+	return merged
+}
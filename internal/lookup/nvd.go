@@ -0,0 +1,365 @@
+package lookup
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/NikolaB131/nmap-vulners-service/internal/entity"
+)
+
+var (
+	ErrNoNVDFeeds = errors.New("no nvd feeds found")
+)
+
+// NVD searches vulnerabilities in NVD CVE JSON 2.0 feed files loaded from disk, no network access is needed.
+type NVD struct {
+	products map[string][]nvdMatch // keyed by "part:vendor:product" of a CPE
+	names    map[string][]string   // product name to products keys, for a software name search
+}
+
+// nvdMatch is a vulnerable cpeMatch entry of a CVE configuration.
+type nvdMatch struct {
+	cveID     string
+	cvss      float32
+	version   string // "*" means any version within the range below
+	startIncl string
+	startExcl string
+	endIncl   string
+	endExcl   string
+}
+
+type nvdFeed struct {
+	Vulnerabilities []struct {
+		CVE struct {
+			ID      string `json:"id"`
+			Metrics struct {
+				V31 []nvdMetric `json:"cvssMetricV31"`
+				V30 []nvdMetric `json:"cvssMetricV30"`
+				V2  []nvdMetric `json:"cvssMetricV2"`
+			} `json:"metrics"`
+			Configurations []struct {
+				Nodes []struct {
+					CPEMatch []struct {
+						Vulnerable            bool   `json:"vulnerable"`
+						Criteria              string `json:"criteria"`
+						VersionStartIncluding string `json:"versionStartIncluding"`
+						VersionStartExcluding string `json:"versionStartExcluding"`
+						VersionEndIncluding   string `json:"versionEndIncluding"`
+						VersionEndExcluding   string `json:"versionEndExcluding"`
+					} `json:"cpeMatch"`
+				} `json:"nodes"`
+			} `json:"configurations"`
+		} `json:"cve"`
+	} `json:"vulnerabilities"`
+}
+
+type nvdMetric struct {
+	Type     string `json:"type"` // Primary or Secondary
+	CVSSData struct {
+		BaseScore float32 `json:"baseScore"`
+	} `json:"cvssData"`
+}
+
+// NewNVD loads every *.json and *.json.gz feed file of dir into memory.
+func NewNVD(logger *slog.Logger, dir string) (*NVD, error) {
+	var paths []string
+	for _, pattern := range []string{"*.json", "*.json.gz"} {
+		matched, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, matched...)
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("%w in %s", ErrNoNVDFeeds, dir)
+	}
+
+	nvd := &NVD{
+		products: make(map[string][]nvdMatch),
+		names:    make(map[string][]string),
+	}
+	cves := 0
+	for _, path := range paths {
+		count, err := nvd.loadFeed(path)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load nvd feed %s: %w", filepath.Base(path), err)
+		}
+		cves += count
+	}
+
+	logger.Info("NVD feeds loaded", slog.Int("files", len(paths)), slog.Int("cves", cves), slog.Int("products", len(nvd.products)))
+	return nvd, nil
+}
+
+func (n *NVD) loadFeed(path string) (int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	var reader io.Reader = file
+	if strings.HasSuffix(path, ".gz") {
+		gzipReader, err := gzip.NewReader(file)
+		if err != nil {
+			return 0, err
+		}
+		defer gzipReader.Close()
+		reader = gzipReader
+	}
+
+	var feed nvdFeed
+	err = json.NewDecoder(reader).Decode(&feed)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, vulnerability := range feed.Vulnerabilities {
+		cve := vulnerability.CVE
+		cvss := nvdScore(cve.Metrics.V31, cve.Metrics.V30, cve.Metrics.V2)
+		// Platform conditions of AND configurations (like "running on Windows") are not checked,
+		// every vulnerable CPE is matched on its own
+		for _, configuration := range cve.Configurations {
+			for _, node := range configuration.Nodes {
+				for _, cpeMatch := range node.CPEMatch {
+					if !cpeMatch.Vulnerable {
+						continue
+					}
+					cpe, ok := parseCPE(cpeMatch.Criteria)
+					if !ok {
+						continue
+					}
+					n.add(cpe, nvdMatch{
+						cveID:     cve.ID,
+						cvss:      cvss,
+						version:   cpe.version,
+						startIncl: cpeMatch.VersionStartIncluding,
+						startExcl: cpeMatch.VersionStartExcluding,
+						endIncl:   cpeMatch.VersionEndIncluding,
+						endExcl:   cpeMatch.VersionEndExcluding,
+					})
+				}
+			}
+		}
+	}
+	return len(feed.Vulnerabilities), nil
+}
+
+func (n *NVD) add(cpe cpeName, match nvdMatch) {
+	key := cpe.key()
+	if _, ok := n.products[key]; !ok {
+		n.names[cpe.product] = append(n.names[cpe.product], key)
+	}
+	n.products[key] = append(n.products[key], match)
+}
+
+// Search matches query version against versions and version ranges of the CPE product, or of every product
+// with the same name for a software name search.
+func (n *NVD) Search(ctx context.Context, query Query) ([]entity.Vulnerability, error) {
+	var keys []string
+	version := query.Version
+	switch query.Type {
+	case QueryTypeCPE:
+		cpe, ok := parseCPE(query.Software)
+		if !ok {
+			return nil, nil
+		}
+		keys = []string{cpe.key()}
+		if version == "" {
+			version = cpe.version
+		}
+	case QueryTypeSoftware:
+		keys = n.names[strings.ReplaceAll(strings.ToLower(query.Software), " ", "_")]
+	}
+	if version == "" {
+		return nil, nil
+	}
+
+	var vulns []entity.Vulnerability
+	seen := make(map[string]bool)
+	for _, key := range keys {
+		for _, match := range n.products[key] {
+			if seen[match.cveID] || !match.affects(version) {
+				continue
+			}
+			seen[match.cveID] = true
+			vulns = append(vulns, entity.Vulnerability{
+				Identifier: match.cveID,
+				CvssScore:  match.cvss,
+				Type:       "cve",
+				URL:        nvdURL + match.cveID,
+			})
+		}
+	}
+	return vulns, nil
+}
+
+func (m nvdMatch) affects(version string) bool {
+	switch m.version {
+	case "*", "":
+	case "-": // not applicable
+		return false
+	default:
+		return CompareVersions(version, m.version) == 0
+	}
+
+	if m.startIncl != "" && CompareVersions(version, m.startIncl) < 0 {
+		return false
+	}
+	if m.startExcl != "" && CompareVersions(version, m.startExcl) <= 0 {
+		return false
+	}
+	if m.endIncl != "" && CompareVersions(version, m.endIncl) > 0 {
+		return false
+	}
+	if m.endExcl != "" && CompareVersions(version, m.endExcl) >= 0 {
+		return false
+	}
+	return true
+}
+
+// nvdScore prefers the newest CVSS version and the primary (NVD) score over the ones from other sources.
+func nvdScore(metricsByVersion ...[]nvdMetric) float32 {
+	for _, metrics := range metricsByVersion {
+		if len(metrics) == 0 {
+			continue
+		}
+		for _, metric := range metrics {
+			if metric.Type == "Primary" {
+				return metric.CVSSData.BaseScore
+			}
+		}
+		return metrics[0].CVSSData.BaseScore
+	}
+	return 0
+}
+
+type cpeName struct {
+	part    string
+	vendor  string
+	product string
+	version string
+}
+
+func (c cpeName) key() string {
+	return c.part + ":" + c.vendor + ":" + c.product
+}
+
+// parseCPE accepts both URI (cpe:/a:vendor:product:version) and formatted string (cpe:2.3:a:vendor:product:version:...) bindings.
+func parseCPE(cpe string) (cpeName, bool) {
+	var parts []string
+	switch {
+	case strings.HasPrefix(cpe, "cpe:2.3:"):
+		parts = strings.Split(strings.TrimPrefix(cpe, "cpe:2.3:"), ":")
+	case strings.HasPrefix(cpe, "cpe:/"):
+		parts = strings.Split(strings.TrimPrefix(cpe, "cpe:/"), ":")
+	default:
+		return cpeName{}, false
+	}
+	if len(parts) < 3 {
+		return cpeName{}, false
+	}
+
+	name := cpeName{part: parts[0], vendor: strings.ToLower(parts[1]), product: strings.ToLower(parts[2])}
+	if len(parts) > 3 {
+		name.version = strings.ReplaceAll(parts[3], `\`, "")
+	}
+	return name, true
+}
+
+// nvdURL is the NVD page of a CVE without its identifier
+const nvdURL = "https://nvd.nist.gov/vuln/detail/"
+
+// preReleases are labels of versions released before the one they are appended to, e.g. "2.0rc1" is older than "2.0".
+// Other labels mark later releases, e.g. "7.4p1" of OpenSSH or "1.1.1a" of OpenSSL.
+var preReleases = []string{"alpha", "beta", "pre", "rc", "dev", "preview"}
+
+// CompareVersions compares versions segment by segment, numeric runs as numbers and the rest as strings,
+// so "1.10" is greater than "1.9". Missing numeric segments are zeros, so "2.4" equals "2.4.0".
+// A version with a pre-release label like "2.0rc1" is less than "2.0", with other labels like "7.4p1" greater than "7.4".
+func CompareVersions(a string, b string) int {
+	aSegments, bSegments := versionSegments(a), versionSegments(b)
+	for i := 0; i < len(aSegments) || i < len(bSegments); i++ {
+		aSegment, bSegment := "0", "0"
+		if i < len(aSegments) {
+			aSegment = aSegments[i]
+		} else if !isNumeric(bSegments[i]) {
+			return labelOrder(bSegments[i])
+		}
+		if i < len(bSegments) {
+			bSegment = bSegments[i]
+		} else if !isNumeric(aSegments[i]) {
+			return -labelOrder(aSegments[i])
+		}
+
+		aNum, aErr := strconv.Atoi(aSegment)
+		bNum, bErr := strconv.Atoi(bSegment)
+		switch {
+		case aErr == nil && bErr == nil:
+			if aNum != bNum {
+				if aNum < bNum {
+					return -1
+				}
+				return 1
+			}
+		case aErr == nil: // number is greater than a label like "rc1"
+			return 1
+		case bErr == nil:
+			return -1
+		default:
+			if c := strings.Compare(aSegment, bSegment); c != 0 {
+				return c
+			}
+		}
+	}
+	return 0
+}
+
+// labelOrder compares a version without label with the same version followed by label.
+func labelOrder(label string) int {
+	if slices.Contains(preReleases, label) {
+		return 1
+	}
+	return -1
+}
+
+func isNumeric(segment string) bool {
+	_, err := strconv.Atoi(segment)
+	return err == nil
+}
+
+// versionSegments splits "1.2.3p1" into "1", "2", "3", "p", "1".
+func versionSegments(version string) []string {
+	var segments []string
+	var current []rune
+	flush := func() {
+		if len(current) > 0 {
+			segments = append(segments, string(current))
+			current = current[:0]
+		}
+	}
+	for _, r := range strings.ToLower(version) {
+		switch {
+		case r == '.' || r == '-' || r == '_' || r == ':':
+			flush()
+		case len(current) > 0 && unicode.IsDigit(r) != unicode.IsDigit(current[0]):
+			flush()
+			current = append(current, r)
+		default:
+			current = append(current, r)
+		}
+	}
+	flush()
+	return segments
+}
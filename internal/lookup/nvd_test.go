@@ -0,0 +1,31 @@
+package lookup_test
+
+import (
+	"testing"
+
+	"github.com/NikolaB131/nmap-vulners-service/internal/lookup"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"1.10", "1.9", 1},
+		{"0.8.1", "0.8.1", 0},
+		{"2.4.0", "2.4", 0},
+		{"2.4", "2.4.0.0", 0},
+		{"2.4.1", "2.4", 1},
+		{"2.0rc1", "2.0", -1},
+		{"2.0-beta", "2.0.0", -1},
+		{"2.0rc1", "2.0rc2", -1},
+		{"2.0.1", "2.0rc1", 1},
+		{"7.4p1", "7.4", 1},
+		{"1.1.1a", "1.1.1", 1},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, lookup.CompareVersions(test.a, test.b), "%s vs %s", test.a, test.b)
+		assert.Equal(t, -test.expected, lookup.CompareVersions(test.b, test.a), "%s vs %s", test.b, test.a)
+	}
+}
@@ -0,0 +1,144 @@
+package lookup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/NikolaB131/nmap-vulners-service/internal/entity"
+)
+
+const (
+	DefaultVulnersAPIURL = "https://vulners.com"
+	vulnersSoftwarePath  = "/api/v3/burp/software/"
+
+	vulnersAttempts   = 3
+	vulnersRetryDelay = time.Second
+)
+
+var (
+	ErrVulnersAPI = errors.New("vulners api error")
+)
+
+// VulnersAPI searches the vulners.com burp/software API, the same one vulners.nse uses.
+type VulnersAPI struct {
+	apiURL string
+	apiKey string
+	client *http.Client
+}
+
+// NewVulnersAPI creates a vulners API source. Empty apiURL means vulners.com, empty apiKey is not sent.
+func NewVulnersAPI(apiURL string, apiKey string, timeout time.Duration) *VulnersAPI {
+	if apiURL == "" {
+		apiURL = DefaultVulnersAPIURL
+	}
+	return &VulnersAPI{
+		apiURL: strings.TrimRight(apiURL, "/"),
+		apiKey: apiKey,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+type vulnersResponse struct {
+	Result string `json:"result"`
+	Data   struct {
+		Error  string `json:"error"`
+		Search []struct {
+			Source struct {
+				ID             string `json:"id"`
+				Type           string `json:"type"`
+				BulletinFamily string `json:"bulletinFamily"`
+				CVSS           struct {
+					Score float32 `json:"score"`
+				} `json:"cvss"`
+			} `json:"_source"`
+		} `json:"search"`
+	} `json:"data"`
+}
+
+// Search makes several attempts if vulners cannot be reached or answers with a server error.
+func (a *VulnersAPI) Search(ctx context.Context, query Query) ([]entity.Vulnerability, error) {
+	values := url.Values{}
+	values.Set("software", query.Software)
+	values.Set("version", query.Version)
+	values.Set("type", string(query.Type))
+	requestURL := a.apiURL + vulnersSoftwarePath + "?" + values.Encode()
+
+	var body []byte
+	var err error
+	for attempt := 1; attempt <= vulnersAttempts; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-time.After(vulnersRetryDelay):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+
+		var retry bool
+		body, retry, err = a.get(ctx, requestURL)
+		if err == nil || !retry || ctx.Err() != nil {
+			break
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var response vulnersResponse
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to decode response: %s", ErrVulnersAPI, err)
+	}
+	switch response.Result {
+	case "OK":
+	case "warning": // nothing found
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("%w: result %q: %s", ErrVulnersAPI, response.Result, response.Data.Error)
+	}
+
+	vulns := make([]entity.Vulnerability, 0, len(response.Data.Search))
+	for _, found := range response.Data.Search {
+		vulns = append(vulns, entity.Vulnerability{
+			Identifier: found.Source.ID,
+			CvssScore:  found.Source.CVSS.Score,
+			Type:       found.Source.Type,
+			IsExploit:  strings.EqualFold(found.Source.BulletinFamily, "exploit"),
+		})
+	}
+	return vulns, nil
+}
+
+// get returns the response body, retry tells whether the error is worth another attempt.
+func (a *VulnersAPI) get(ctx context.Context, requestURL string) ([]byte, bool, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	request.Header.Set("User-Agent", "nmap-vulners-service")
+	if a.apiKey != "" {
+		request.Header.Set("X-Api-Key", a.apiKey)
+	}
+
+	response, err := a.client.Do(request)
+	if err != nil {
+		return nil, true, fmt.Errorf("%w: %s", ErrVulnersAPI, err)
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, true, fmt.Errorf("%w: unable to read response: %s", ErrVulnersAPI, err)
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, response.StatusCode >= http.StatusInternalServerError, fmt.Errorf("%w: status %d", ErrVulnersAPI, response.StatusCode)
+	}
+	return body, false, nil
+}
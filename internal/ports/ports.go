@@ -0,0 +1,193 @@
+// Package ports validates port lists of scan requests and expands presets like top-100 into nmap port lists.
+package ports
+
+import (
+	"bufio"
+	"cmp"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/NikolaB131/nmap-vulners-service/pkg/sl"
+)
+
+type Protocol string
+
+const (
+	TCP Protocol = "tcp"
+	UDP Protocol = "udp"
+)
+
+const (
+	presetAll    = "all"
+	presetTopPfx = "top-" // top-100, top-1000 or any other number of the most frequently open ports
+)
+
+var (
+	ErrInvalidPorts      = errors.New("invalid ports")
+	ErrPresetUnavailable = errors.New("port preset is unavailable, nmap-services file was not loaded")
+)
+
+// Parser turns port items like "22", "8000-8100", "top-100" and "all" into a sorted nmap port list
+// without duplicates, where adjacent ports are joined into ranges.
+type Parser struct {
+	top        map[Protocol][]uint16 // ports of nmap-services sorted by open frequency, nil if the file is not loaded
+	defaultTCP []string
+}
+
+// NewParser loads top ports from nmapServicesPath (nmap-services file of nmap installation), presets top-N
+// are unavailable if it cannot be read. defaultTCP is used when a request has no ports at all, empty means
+// nmap's own default (the top 1000 TCP ports of the nmap-services built into nmap). If defaultTCP has a preset
+// which is unavailable, nmap's own default is used too, so the service starts without nmap-services file.
+func NewParser(logger *slog.Logger, nmapServicesPath string, defaultTCP []string) (*Parser, error) {
+	parser := &Parser{}
+
+	top, err := readNmapServices(nmapServicesPath)
+	if err != nil {
+		logger.Warn("unable to load nmap-services, top ports presets are unavailable", slog.String("path", nmapServicesPath), sl.Err(err))
+	} else {
+		parser.top = top
+	}
+
+	parser.defaultTCP, err = parser.Parse(TCP, defaultTCP)
+	if errors.Is(err, ErrPresetUnavailable) {
+		logger.Warn("default tcp ports are unavailable, nmap default ports are scanned instead", slog.String("default_tcp_ports", strings.Join(defaultTCP, ",")), sl.Err(err))
+		parser.defaultTCP = nil
+	} else if err != nil {
+		return nil, fmt.Errorf("default tcp ports: %w", err)
+	}
+	return parser, nil
+}
+
+// DefaultTCP returns normalized default TCP ports, empty means nmap default ports.
+func (p *Parser) DefaultTCP() []string {
+	return slices.Clone(p.defaultTCP)
+}
+
+// Parse validates items and returns them normalized, empty items give empty result.
+func (p *Parser) Parse(protocol Protocol, items []string) ([]string, error) {
+	var ranges [][2]uint16
+	for _, item := range items {
+		switch {
+		case item == presetAll:
+			ranges = append(ranges, [2]uint16{1, 65535})
+		case strings.HasPrefix(item, presetTopPfx):
+			count, err := strconv.Atoi(strings.TrimPrefix(item, presetTopPfx))
+			if err != nil || count < 1 {
+				return nil, fmt.Errorf("%w: %q is not a top-N preset with a positive N", ErrInvalidPorts, item)
+			}
+			if p.top == nil {
+				return nil, fmt.Errorf("%w: %s", ErrPresetUnavailable, item)
+			}
+			top := p.top[protocol]
+			for _, port := range top[:min(count, len(top))] {
+				ranges = append(ranges, [2]uint16{port, port})
+			}
+		default:
+			low, high, isRange := strings.Cut(item, "-")
+			if !isRange {
+				high = low
+			}
+			lowPort, err := parsePort(low)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %q: %s", ErrInvalidPorts, item, err)
+			}
+			highPort, err := parsePort(high)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %q: %s", ErrInvalidPorts, item, err)
+			}
+			if lowPort > highPort {
+				return nil, fmt.Errorf("%w: range %q is reversed", ErrInvalidPorts, item)
+			}
+			ranges = append(ranges, [2]uint16{lowPort, highPort})
+		}
+	}
+
+	return join(ranges), nil
+}
+
+func parsePort(value string) (uint16, error) {
+	port, err := strconv.Atoi(value)
+	if err != nil || port < 1 || port > 65535 {
+		return 0, fmt.Errorf("port must be a number from 1 to 65535, got %q", value)
+	}
+	return uint16(port), nil
+}
+
+// join sorts ranges and merges overlapping and adjacent ones.
+func join(ranges [][2]uint16) []string {
+	slices.SortFunc(ranges, func(a, b [2]uint16) int { return cmp.Compare(a[0], b[0]) })
+
+	var merged [][2]uint16
+	for _, r := range ranges {
+		last := len(merged) - 1
+		if last >= 0 && int(r[0]) <= int(merged[last][1])+1 {
+			merged[last][1] = max(merged[last][1], r[1])
+			continue
+		}
+		merged = append(merged, r)
+	}
+
+	items := make([]string, len(merged))
+	for i, r := range merged {
+		items[i] = strconv.Itoa(int(r[0]))
+		if r[1] != r[0] {
+			items[i] += "-" + strconv.Itoa(int(r[1]))
+		}
+	}
+	return items
+}
+
+// readNmapServices reads lines like "http	80/tcp	0.484143	# World Wide Web HTTP"
+// and returns ports of every protocol sorted by open frequency, as nmap does for --top-ports.
+func readNmapServices(path string) (map[Protocol][]uint16, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	type service struct {
+		port      uint16
+		frequency float64
+	}
+	services := make(map[Protocol][]service)
+
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		portValue, protocol, ok := strings.Cut(fields[1], "/")
+		if !ok || (Protocol(protocol) != TCP && Protocol(protocol) != UDP) {
+			continue
+		}
+		port, err := parsePort(portValue)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		frequency, err := strconv.ParseFloat(fields[2], 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid frequency: %w", line, err)
+		}
+		services[Protocol(protocol)] = append(services[Protocol(protocol)], service{port: port, frequency: frequency})
+	}
+	err = scanner.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	top := make(map[Protocol][]uint16, len(services))
+	for protocol, protocolServices := range services {
+		slices.SortStableFunc(protocolServices, func(a, b service) int { return cmp.Compare(b.frequency, a.frequency) })
+		for _, s := range protocolServices {
+			top[protocol] = append(top[protocol], s.port)
+		}
+	}
+	return top, nil
+}
@@ -0,0 +1,77 @@
+package ports_test
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/NikolaB131/nmap-vulners-service/internal/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newParser(t *testing.T) *ports.Parser {
+	parser, err := ports.NewParser(slog.Default(), "./testdata/nmap-services", []string{"top-1000"})
+	require.NoError(t, err)
+	return parser
+}
+
+func TestParse(t *testing.T) {
+	parser := newParser(t)
+	tests := []struct {
+		items    []string
+		expected []string
+	}{
+		{nil, []string{}},
+		{[]string{"443", "22", "80"}, []string{"22", "80", "443"}},
+		{[]string{"22", "22", "8000-8100", "8050-8200", "8201"}, []string{"22", "8000-8201"}},
+		{[]string{"1-100", "21", "all"}, []string{"1-65535"}},
+		{[]string{"top-3"}, []string{"23", "80", "443"}},
+		{[]string{"top-4", "443", "22"}, []string{"21-23", "80", "443"}},
+	}
+	for _, test := range tests {
+		parsed, err := parser.Parse(ports.TCP, test.items)
+		require.NoError(t, err, test.items)
+		assert.Equal(t, test.expected, parsed, test.items)
+	}
+
+	udp, err := parser.Parse(ports.UDP, []string{"top-2"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"137", "161"}, udp, "presets are taken by protocol")
+}
+
+func TestParseInvalid(t *testing.T) {
+	parser := newParser(t)
+	for _, item := range []string{"0", "-1", "65536", "http", "100-50", "1-", "top-0", "top-x", " 22"} {
+		_, err := parser.Parse(ports.TCP, []string{item})
+		assert.ErrorIs(t, err, ports.ErrInvalidPorts, item)
+	}
+}
+
+func TestDefault(t *testing.T) {
+	assert.Equal(t,
+		[]string{"1", "21-23", "25", "53", "80", "110", "139", "443", "445", "3389", "11001"},
+		newParser(t).DefaultTCP(),
+		"all TCP ports of nmap-services, as it has less than 1000",
+	)
+
+	_, err := ports.NewParser(slog.Default(), "./testdata/nmap-services", []string{"22", "bad"})
+	assert.ErrorIs(t, err, ports.ErrInvalidPorts)
+
+	parser, err := ports.NewParser(slog.Default(), "./testdata/nmap-services", nil)
+	require.NoError(t, err)
+	assert.Empty(t, parser.DefaultTCP(), "nmap default ports")
+}
+
+func TestNmapServicesUnavailable(t *testing.T) {
+	parser, err := ports.NewParser(slog.Default(), "./testdata/missing-nmap-services", []string{"top-1000"})
+	require.NoError(t, err, "the service starts without nmap-services")
+	assert.Empty(t, parser.DefaultTCP(), "nmap default ports are scanned instead of the unavailable preset")
+
+	parser, err = ports.NewParser(slog.Default(), "./testdata/missing-nmap-services", []string{"1-1024"})
+	require.NoError(t, err)
+	_, err = parser.Parse(ports.TCP, []string{"top-100"})
+	assert.ErrorIs(t, err, ports.ErrPresetUnavailable)
+	parsed, err := parser.Parse(ports.TCP, []string{"all"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1-65535"}, parsed)
+}
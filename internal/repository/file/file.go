@@ -0,0 +1,112 @@
+package file
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/NikolaB131/nmap-vulners-service/internal/entity"
+	"github.com/NikolaB131/nmap-vulners-service/internal/repository"
+	"github.com/NikolaB131/nmap-vulners-service/pkg/sl"
+)
+
+// ScanRepository stores every scan as a separate JSON file in a directory.
+type ScanRepository struct {
+	log *slog.Logger
+	dir string
+	mu  sync.RWMutex
+}
+
+func NewScanRepository(logger *slog.Logger, dir string) (*ScanRepository, error) {
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create scans directory: %w", err)
+	}
+	return &ScanRepository{log: logger, dir: dir}, nil
+}
+
+func (r *ScanRepository) Save(ctx context.Context, scan entity.Scan) error {
+	data, err := json.Marshal(scan)
+	if err != nil {
+		return fmt.Errorf("unable to encode scan: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Write to a temporary file first, so a crash never leaves a half written scan behind
+	tmpPath := r.path(scan.ID) + ".tmp"
+	err = os.WriteFile(tmpPath, data, 0o644)
+	if err != nil {
+		return fmt.Errorf("unable to write scan: %w", err)
+	}
+	err = os.Rename(tmpPath, r.path(scan.ID))
+	if err != nil {
+		return fmt.Errorf("unable to write scan: %w", err)
+	}
+	return nil
+}
+
+func (r *ScanRepository) Get(ctx context.Context, id string) (entity.Scan, error) {
+	// Scan IDs come from clients, do not let them point outside the directory
+	if id == "" || strings.ContainsAny(id, `/\.`) {
+		return entity.Scan{}, repository.ErrScanNotFound
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.read(r.path(id))
+}
+
+func (r *ScanRepository) List(ctx context.Context, filter repository.ScanFilter) ([]entity.Scan, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	paths, err := filepath.Glob(filepath.Join(r.dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	// A broken file, e.g. written by hand or truncated by a full disk, must not hide the other scans
+	scans := make([]entity.Scan, 0, len(paths))
+	for _, path := range paths {
+		scan, err := r.read(path)
+		if errors.Is(err, repository.ErrScanNotFound) {
+			continue // deleted after Glob
+		}
+		if err != nil {
+			r.log.Warn("unable to read stored scan, it is skipped", slog.String("path", path), sl.Err(err))
+			continue
+		}
+		scans = append(scans, scan)
+	}
+	return filter.Apply(scans), nil
+}
+
+func (r *ScanRepository) path(id string) string {
+	return filepath.Join(r.dir, id+".json")
+}
+
+func (r *ScanRepository) read(path string) (entity.Scan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return entity.Scan{}, repository.ErrScanNotFound
+		}
+		return entity.Scan{}, fmt.Errorf("unable to read scan: %w", err)
+	}
+
+	var scan entity.Scan
+	err = json.Unmarshal(data, &scan)
+	if err != nil {
+		return entity.Scan{}, fmt.Errorf("unable to decode scan %s: %w", filepath.Base(path), err)
+	}
+	return scan, nil
+}
@@ -0,0 +1,99 @@
+package file_test
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/NikolaB131/nmap-vulners-service/internal/entity"
+	"github.com/NikolaB131/nmap-vulners-service/internal/repository"
+	"github.com/NikolaB131/nmap-vulners-service/internal/repository/file"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRepository(t *testing.T, dir string) *file.ScanRepository {
+	scanRepository, err := file.NewScanRepository(slog.Default(), dir)
+	require.NoError(t, err)
+	return scanRepository
+}
+
+func testScan(id string, createdAt time.Time) entity.Scan {
+	return entity.Scan{
+		ID:         id,
+		Status:     entity.ScanStatusDone,
+		Targets:    []string{"localhost"},
+		TcpPorts:   []string{"22", "8000-8100"},
+		CreatedAt:  createdAt,
+		StartedAt:  createdAt,
+		FinishedAt: createdAt.Add(time.Minute),
+		Results: []entity.HostResult{{
+			TargetIP: "127.0.0.1",
+			Services: []entity.Service{{
+				Name:    "libssh",
+				Version: "0.8.1",
+				TcpPort: 22,
+				CPEs:    []string{"cpe:/a:libssh:libssh:0.8.1"},
+				Vulns: []entity.Vulnerability{{
+					Identifier: "CVE-2018-10933",
+					CvssScore:  6.4,
+					Type:       "cve",
+					URL:        "https://vulners.com/cve/CVE-2018-10933",
+					CPE:        "cpe:/a:libssh:libssh:0.8.1",
+				}},
+			}},
+		}},
+		Stats: entity.ScanStats{Elapsed: 12 * time.Second, HostsUp: 1, HostsTotal: 1},
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	createdAt := time.Date(2024, 5, 11, 12, 0, 0, 0, time.UTC)
+	older, newer := testScan("older", createdAt), testScan("newer", createdAt.Add(time.Hour))
+
+	scanRepository := newRepository(t, dir)
+	require.NoError(t, scanRepository.Save(ctx, older))
+	require.NoError(t, scanRepository.Save(ctx, newer))
+
+	// A new repository over the same directory imitates a restart
+	restarted := newRepository(t, dir)
+	scan, err := restarted.Get(ctx, "older")
+	require.NoError(t, err)
+	assert.Equal(t, older, scan)
+
+	scans, err := restarted.List(ctx, repository.ScanFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, []entity.Scan{newer, older}, scans, "newest first")
+
+	data, err := os.ReadFile(filepath.Join(dir, "older.json"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"target_ip":"127.0.0.1"`, "stored with json field names")
+
+	_, err = restarted.Get(ctx, "missing")
+	assert.ErrorIs(t, err, repository.ErrScanNotFound)
+	_, err = restarted.Get(ctx, "../older")
+	assert.ErrorIs(t, err, repository.ErrScanNotFound)
+}
+
+func TestListSkipsBrokenFiles(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	scanRepository := newRepository(t, dir)
+	require.NoError(t, scanRepository.Save(ctx, testScan("valid", time.Now())))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "corrupt.json"), []byte(`{"id": "corrupt", "status": "do`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "half-written.json.tmp"), []byte(`{"id": "half`), 0o644))
+
+	scans, err := newRepository(t, dir).List(ctx, repository.ScanFilter{})
+	require.NoError(t, err)
+	require.Len(t, scans, 1)
+	assert.Equal(t, "valid", scans[0].ID)
+
+	_, err = scanRepository.Get(ctx, "corrupt")
+	assert.Error(t, err)
+}
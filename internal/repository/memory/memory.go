@@ -0,0 +1,49 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/NikolaB131/nmap-vulners-service/internal/entity"
+	"github.com/NikolaB131/nmap-vulners-service/internal/repository"
+)
+
+// ScanRepository keeps scans in memory, so history is lost on restart.
+type ScanRepository struct {
+	mu    sync.RWMutex
+	scans map[string]entity.Scan
+}
+
+func NewScanRepository() *ScanRepository {
+	return &ScanRepository{scans: make(map[string]entity.Scan)}
+}
+
+func (r *ScanRepository) Save(ctx context.Context, scan entity.Scan) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.scans[scan.ID] = scan
+	return nil
+}
+
+func (r *ScanRepository) Get(ctx context.Context, id string) (entity.Scan, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	scan, ok := r.scans[id]
+	if !ok {
+		return entity.Scan{}, repository.ErrScanNotFound
+	}
+	return scan, nil
+}
+
+func (r *ScanRepository) List(ctx context.Context, filter repository.ScanFilter) ([]entity.Scan, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	scans := make([]entity.Scan, 0, len(r.scans))
+	for _, scan := range r.scans {
+		scans = append(scans, scan)
+	}
+	return filter.Apply(scans), nil
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/NikolaB131/nmap-vulners-service/internal/entity"
+)
+
+var (
+	ErrScanNotFound = errors.New("scan not found")
+)
+
+// ScanRepository stores finished scans.
+type ScanRepository interface {
+	Save(ctx context.Context, scan entity.Scan) error
+	Get(ctx context.Context, id string) (entity.Scan, error)
+	// List returns scans matching the filter, newest first.
+	List(ctx context.Context, filter ScanFilter) ([]entity.Scan, error)
+}
+
+type ScanFilter struct {
+	Target string    // requested target or scanned host IP, empty means any
+	Since  time.Time // zero means no lower bound
+	Limit  int       // 0 means no limit
+}
+
+// Match reports whether scan satisfies the filter, ignoring Limit.
+func (f ScanFilter) Match(scan entity.Scan) bool {
+	if !f.Since.IsZero() && scan.CreatedAt.Before(f.Since) {
+		return false
+	}
+	if f.Target == "" || slices.Contains(scan.Targets, f.Target) {
+		return true
+	}
+	for _, host := range scan.Results {
+		if host.TargetIP == f.Target {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply sorts scans newest first, drops the ones not matching the filter and cuts the result to Limit.
+func (f ScanFilter) Apply(scans []entity.Scan) []entity.Scan {
+	filtered := make([]entity.Scan, 0, len(scans))
+	for _, scan := range scans {
+		if f.Match(scan) {
+			filtered = append(filtered, scan)
+		}
+	}
+
+	slices.SortFunc(filtered, func(a, b entity.Scan) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+
+	if f.Limit > 0 && len(filtered) > f.Limit {
+		filtered = filtered[:f.Limit]
+	}
+	return filtered
+}
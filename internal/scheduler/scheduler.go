@@ -0,0 +1,166 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/netip"
+	"strings"
+	"time"
+
+	"github.com/NikolaB131/nmap-vulners-service/internal/entity"
+	"github.com/NikolaB131/nmap-vulners-service/internal/service"
+	"github.com/NikolaB131/nmap-vulners-service/internal/target"
+	"github.com/NikolaB131/nmap-vulners-service/pkg/sl"
+	"github.com/robfig/cron/v3"
+)
+
+type ScansService interface {
+	Run(ctx context.Context, targets []string, addresses map[string][]string, tcpPorts []string, udpPorts []string, filter entity.VulnsFilter, onTargetDone service.TargetDoneFunc) (entity.Scan, error)
+}
+
+// Schedule is a recurring scan, it runs either by Cron expression or every Interval.
+type Schedule struct {
+	Name     string
+	Targets  []string
+	TcpPorts []string
+	UdpPorts []string
+	Cron     string // standard 5 fields expression or descriptor like @daily
+	Interval time.Duration
+}
+
+// Scheduler runs recurring scans in background. A schedule is skipped while its previous run is still in progress.
+type Scheduler struct {
+	log     *slog.Logger
+	scans   ScansService
+	targets *target.Parser
+	scope   *target.Policy // the global scope, schedules have no client address
+	cron    *cron.Cron
+	entries map[cron.EntryID]string // schedule names
+	ctx     context.Context
+	cancel  context.CancelFunc
+}
+
+// New checks schedules like targets of a gRPC request: their syntax, the max_hosts limit and the scope.
+func New(logger *slog.Logger, scans ScansService, targets *target.Parser, scope *target.Policy, schedules []Schedule) (*Scheduler, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cronLog := cronLogger{log: logger}
+	s := &Scheduler{
+		log:     logger,
+		scans:   scans,
+		targets: targets,
+		scope:   scope,
+		cron:    cron.New(cron.WithLogger(cronLog), cron.WithChain(cron.Recover(cronLog))),
+		entries: make(map[cron.EntryID]string, len(schedules)),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+
+	for _, schedule := range schedules {
+		cronSchedule, err := parseSchedule(schedule)
+		if err != nil {
+			cancel()
+			return nil, fmt.Errorf("schedule %q: %w", schedule.Name, err)
+		}
+		_, err = s.parseTargets(ctx, schedule)
+		if err != nil {
+			cancel()
+			return nil, fmt.Errorf("schedule %q: %w", schedule.Name, err)
+		}
+		id := s.cron.Schedule(cronSchedule, cron.NewChain(cron.SkipIfStillRunning(cronLog)).Then(s.job(schedule)))
+		s.entries[id] = schedule.Name
+	}
+
+	return s, nil
+}
+
+func (s *Scheduler) Start() {
+	s.cron.Start()
+	for _, entry := range s.cron.Entries() {
+		s.log.Info("scheduled scan registered", slog.String("schedule", s.entries[entry.ID]), slog.Time("next_run", entry.Next))
+	}
+}
+
+// Stop cancels running scans and waits for them to finish.
+func (s *Scheduler) Stop() {
+	s.cancel()
+	<-s.cron.Stop().Done()
+}
+
+func (s *Scheduler) job(schedule Schedule) cron.Job {
+	return cron.FuncJob(func() {
+		s.log.Info("scheduled scan started", slog.String("schedule", schedule.Name))
+
+		// Checked again, as hostnames can be resolved to other addresses since the schedule was loaded
+		targets, err := s.parseTargets(s.ctx, schedule)
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				return
+			}
+			s.log.Error("scheduled scan failed", slog.String("schedule", schedule.Name), sl.Err(err))
+			return
+		}
+
+		scan, err := s.scans.Run(s.ctx, schedule.Targets, target.HostnameAddresses(targets), schedule.TcpPorts, schedule.UdpPorts, entity.VulnsFilter{}, nil)
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				return
+			}
+			s.log.Error("scheduled scan failed", slog.String("schedule", schedule.Name), sl.Err(err))
+			return
+		}
+
+		s.log.Info(
+			"scheduled scan done",
+			slog.String("schedule", schedule.Name),
+			slog.String("scan_id", scan.ID),
+			slog.Int("hosts", len(scan.Results)),
+		)
+	})
+}
+
+func (s *Scheduler) parseTargets(ctx context.Context, schedule Schedule) ([]target.Target, error) {
+	if len(schedule.Targets) == 0 {
+		return nil, errors.New("targets are required")
+	}
+	targets, err := s.targets.ParseAll(ctx, schedule.Targets)
+	if err != nil {
+		return nil, err
+	}
+	forbidden := s.scope.Forbidden(netip.Addr{}, targets)
+	if len(forbidden) > 0 {
+		return nil, fmt.Errorf("targets are out of allowed scope: %s", strings.Join(forbidden, ", "))
+	}
+	return targets, nil
+}
+
+func parseSchedule(schedule Schedule) (cron.Schedule, error) {
+	switch {
+	case schedule.Cron != "" && schedule.Interval != 0:
+		return nil, errors.New("only one of cron and interval can be set")
+	case schedule.Cron != "":
+		cronSchedule, err := cron.ParseStandard(schedule.Cron)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron expression: %w", err)
+		}
+		return cronSchedule, nil
+	case schedule.Interval > 0:
+		return cron.Every(schedule.Interval), nil
+	default:
+		return nil, errors.New("cron or positive interval is required")
+	}
+}
+
+// cronLogger passes cron library logs to slog.
+type cronLogger struct {
+	log *slog.Logger
+}
+
+func (l cronLogger) Info(msg string, keysAndValues ...any) {
+	l.log.Debug("cron: "+msg, keysAndValues...)
+}
+
+func (l cronLogger) Error(err error, msg string, keysAndValues ...any) {
+	l.log.Error("cron: "+msg, append([]any{sl.Err(err)}, keysAndValues...)...)
+}
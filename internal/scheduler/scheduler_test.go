@@ -0,0 +1,117 @@
+package scheduler_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/netip"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/NikolaB131/nmap-vulners-service/internal/entity"
+	"github.com/NikolaB131/nmap-vulners-service/internal/scheduler"
+	"github.com/NikolaB131/nmap-vulners-service/internal/service"
+	"github.com/NikolaB131/nmap-vulners-service/internal/target"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingScans records scheduled runs, every run lasts until the scheduler is stopped
+type blockingScans struct {
+	mu        sync.Mutex
+	runs      int
+	targets   []string
+	addresses map[string][]string
+	tcpPorts  []string
+	canceled  chan struct{}
+}
+
+func (s *blockingScans) Run(ctx context.Context, targets []string, addresses map[string][]string, tcpPorts []string, udpPorts []string, filter entity.VulnsFilter, onTargetDone service.TargetDoneFunc) (entity.Scan, error) {
+	s.mu.Lock()
+	s.runs++
+	s.targets, s.addresses, s.tcpPorts = targets, addresses, tcpPorts
+	s.mu.Unlock()
+
+	<-ctx.Done()
+	close(s.canceled)
+	return entity.Scan{}, ctx.Err()
+}
+
+// localhostResolver resolves only localhost
+type localhostResolver struct{}
+
+func (localhostResolver) LookupNetIP(ctx context.Context, network string, host string) ([]netip.Addr, error) {
+	if host == "localhost" {
+		return []netip.Addr{netip.MustParseAddr("127.0.0.1")}, nil
+	}
+	return nil, errors.New("no such host")
+}
+
+// newScheduler allows schedules of at most 256 hosts out of 192.0.2.0/24
+func newScheduler(scans scheduler.ScansService, schedules []scheduler.Schedule) (*scheduler.Scheduler, error) {
+	scope := target.Scope{Deny: []netip.Prefix{netip.MustParsePrefix("192.0.2.0/24")}}
+	return scheduler.New(slog.Default(), scans, target.NewParser(localhostResolver{}, 256), target.NewPolicy(scope, nil), schedules)
+}
+
+func (s *blockingScans) runCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.runs
+}
+
+func TestOverlappingRunSkipped(t *testing.T) {
+	scans := &blockingScans{canceled: make(chan struct{})}
+	scansScheduler, err := newScheduler(scans, []scheduler.Schedule{
+		{Name: "localhost", Targets: []string{"localhost"}, TcpPorts: []string{"22"}, Interval: time.Second},
+	})
+	require.NoError(t, err)
+	scansScheduler.Start()
+
+	require.Eventually(t, func() bool { return scans.runCount() == 1 }, 3*time.Second, 10*time.Millisecond, "the first run starts")
+	scans.mu.Lock()
+	assert.Equal(t, []string{"localhost"}, scans.targets)
+	assert.Equal(t, map[string][]string{"localhost": {"127.0.0.1"}}, scans.addresses, "the checked address is scanned")
+	assert.Equal(t, []string{"22"}, scans.tcpPorts)
+	scans.mu.Unlock()
+
+	// The schedule fires twice more while the first run is still in progress
+	time.Sleep(2500 * time.Millisecond)
+	assert.Equal(t, 1, scans.runCount(), "runs are skipped while the previous one is in progress")
+
+	scansScheduler.Stop()
+	select {
+	case <-scans.canceled:
+	default:
+		assert.Fail(t, "Stop must cancel the running scan and wait for it")
+	}
+}
+
+func TestInvalidSchedule(t *testing.T) {
+	tests := []scheduler.Schedule{
+		{Name: "none", Targets: []string{"127.0.0.1"}},
+		{Name: "both", Targets: []string{"127.0.0.1"}, Cron: "@daily", Interval: time.Hour},
+		{Name: "typo", Targets: []string{"127.0.0.1"}, Cron: "0 3 * *"},
+		{Name: "negative", Targets: []string{"127.0.0.1"}, Interval: -time.Hour},
+	}
+	for _, schedule := range tests {
+		_, err := newScheduler(&blockingScans{}, []scheduler.Schedule{schedule})
+		assert.ErrorContains(t, err, schedule.Name)
+	}
+}
+
+func TestInvalidScheduleTargets(t *testing.T) {
+	tests := []struct {
+		targets []string
+		err     string
+	}{
+		{nil, "targets are required"},
+		{[]string{"127.0.0.1/33"}, "invalid target"},
+		{[]string{"10.0.0.0/23"}, "more than 256 hosts"},
+		{[]string{"localhost", "192.0.2.1"}, "out of allowed scope: 192.0.2.1"},
+	}
+	for _, test := range tests {
+		_, err := newScheduler(&blockingScans{}, []scheduler.Schedule{{Name: "daily", Targets: test.targets, Cron: "@daily"}})
+		assert.ErrorContains(t, err, test.err, test.targets)
+	}
+}
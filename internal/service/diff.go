@@ -0,0 +1,169 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/NikolaB131/nmap-vulners-service/internal/entity"
+)
+
+var (
+	ErrScanNotDone = errors.New("scan is not done")
+)
+
+// Diff compares two finished scans, reporting changes from oldID to newID.
+func (s *Scans) Diff(ctx context.Context, oldID string, newID string) (entity.ScanDiff, error) {
+	oldScan, err := s.Get(ctx, oldID)
+	if err != nil {
+		return entity.ScanDiff{}, err
+	}
+	newScan, err := s.Get(ctx, newID)
+	if err != nil {
+		return entity.ScanDiff{}, err
+	}
+	if oldScan.Status != entity.ScanStatusDone || newScan.Status != entity.ScanStatusDone {
+		return entity.ScanDiff{}, ErrScanNotDone
+	}
+
+	return diffScans(oldScan, newScan), nil
+}
+
+func diffScans(oldScan entity.Scan, newScan entity.Scan) entity.ScanDiff {
+	diff := entity.ScanDiff{OldScanID: oldScan.ID, NewScanID: newScan.ID}
+
+	// Nothing is known about ports of a target that failed to be scanned in either scan, including hosts
+	// a failed hostname resolved to before, so they are not compared
+	failed := make(map[string]bool)
+	for _, host := range slices.Concat(oldScan.Results, newScan.Results) {
+		if host.Error != "" {
+			failed[host.TargetIP] = true
+		}
+	}
+
+	oldHosts := make(map[string]entity.HostResult, len(oldScan.Results))
+	for _, host := range oldScan.Results {
+		oldHosts[host.TargetIP] = host
+	}
+	newHosts := make(map[string]entity.HostResult, len(newScan.Results))
+	for _, host := range newScan.Results {
+		newHosts[host.TargetIP] = host
+	}
+
+	// Hosts of the new scan go first in their original order, then the ones which disappeared
+	var hostIPs []string
+	for _, host := range newScan.Results {
+		hostIPs = append(hostIPs, host.TargetIP)
+	}
+	for _, host := range oldScan.Results {
+		if _, ok := newHosts[host.TargetIP]; !ok {
+			hostIPs = append(hostIPs, host.TargetIP)
+		}
+	}
+
+	isFailed := func(name string) bool { return failed[name] }
+	for _, ip := range hostIPs {
+		if failed[ip] || slices.ContainsFunc(oldHosts[ip].Hostnames, isFailed) {
+			continue
+		}
+		hostDiff := diffHosts(oldHosts[ip], newHosts[ip])
+		if len(hostDiff.OpenedPorts) == 0 && len(hostDiff.ClosedPorts) == 0 && len(hostDiff.Services) == 0 {
+			continue
+		}
+		hostDiff.TargetIP = ip
+		diff.Hosts = append(diff.Hosts, hostDiff)
+	}
+
+	return diff
+}
+
+func diffHosts(oldHost entity.HostResult, newHost entity.HostResult) entity.HostDiff {
+	var hostDiff entity.HostDiff
+
+	// Closed and filtered ports are reported too, only open ones are compared
+	oldHost.Services = slices.DeleteFunc(slices.Clone(oldHost.Services), func(service entity.Service) bool { return !service.Open() })
+	newHost.Services = slices.DeleteFunc(slices.Clone(newHost.Services), func(service entity.Service) bool { return !service.Open() })
+
+	oldServices := make(map[serviceKey]entity.Service, len(oldHost.Services))
+	for _, service := range oldHost.Services {
+		oldServices[keyOf(service)] = service
+	}
+	newServices := make(map[serviceKey]entity.Service, len(newHost.Services))
+	for _, service := range newHost.Services {
+		newServices[keyOf(service)] = service
+	}
+
+	for _, newService := range newHost.Services {
+		oldService, ok := oldServices[keyOf(newService)]
+		if !ok {
+			hostDiff.OpenedPorts = append(hostDiff.OpenedPorts, newService)
+			continue
+		}
+
+		serviceDiff := diffServices(oldService, newService)
+		if serviceDiff.OldVersion != serviceDiff.NewVersion || len(serviceDiff.NewVulns) > 0 || len(serviceDiff.ResolvedVulns) > 0 {
+			hostDiff.Services = append(hostDiff.Services, serviceDiff)
+		}
+	}
+	for _, oldService := range oldHost.Services {
+		if _, ok := newServices[keyOf(oldService)]; !ok {
+			hostDiff.ClosedPorts = append(hostDiff.ClosedPorts, oldService)
+		}
+	}
+
+	slices.SortFunc(hostDiff.OpenedPorts, compareServices)
+	slices.SortFunc(hostDiff.ClosedPorts, compareServices)
+	return hostDiff
+}
+
+func diffServices(oldService entity.Service, newService entity.Service) entity.ServiceDiff {
+	serviceDiff := entity.ServiceDiff{
+		Protocol:   newService.Protocol,
+		TcpPort:    newService.TcpPort,
+		Name:       newService.Name,
+		OldVersion: oldService.Version,
+		NewVersion: newService.Version,
+	}
+
+	oldVulns := make(map[string]bool, len(oldService.Vulns))
+	for _, vuln := range oldService.Vulns {
+		oldVulns[vuln.Identifier] = true
+	}
+	newVulns := make(map[string]bool, len(newService.Vulns))
+	for _, vuln := range newService.Vulns {
+		newVulns[vuln.Identifier] = true
+	}
+
+	for _, vuln := range newService.Vulns {
+		if !oldVulns[vuln.Identifier] {
+			serviceDiff.NewVulns = append(serviceDiff.NewVulns, vuln)
+		}
+	}
+	for _, vuln := range oldService.Vulns {
+		if !newVulns[vuln.Identifier] {
+			serviceDiff.ResolvedVulns = append(serviceDiff.ResolvedVulns, vuln)
+		}
+	}
+
+	return serviceDiff
+}
+
+// serviceKey identifies a service of a host, the same port number can be used by TCP and UDP services
+type serviceKey struct {
+	protocol string
+	port     uint16
+}
+
+func keyOf(service entity.Service) serviceKey {
+	protocol := service.Protocol
+	if protocol == "" {
+		protocol = "tcp" // scans saved before UDP support have only TCP services without protocol
+	}
+	return serviceKey{protocol: protocol, port: service.TcpPort}
+}
+
+// compareServices orders services by port, TCP before UDP on the same port, as nmap lists them.
+func compareServices(a, b entity.Service) int {
+	return cmp.Or(cmp.Compare(a.TcpPort, b.TcpPort), cmp.Compare(a.Protocol, b.Protocol))
+}
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/NikolaB131/nmap-vulners-service/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffScansWithoutProtocol(t *testing.T) {
+	// Saved before UDP support, services have no protocol
+	oldScan := entity.Scan{ID: "old", Results: []entity.HostResult{{
+		TargetIP: "127.0.0.1",
+		Services: []entity.Service{{Name: "ssh", Version: "0.8.1", TcpPort: 22}},
+	}}}
+	newScan := entity.Scan{ID: "new", Results: []entity.HostResult{{
+		TargetIP: "127.0.0.1",
+		Services: []entity.Service{
+			{Name: "ssh", Version: "0.8.3", TcpPort: 22, Protocol: "tcp", State: entity.PortStateOpen},
+			{Name: "ntp", TcpPort: 123, Protocol: "udp", State: entity.PortStateOpen},
+		},
+	}}}
+
+	diff := diffScans(oldScan, newScan)
+	require.Len(t, diff.Hosts, 1)
+	assert.Empty(t, diff.Hosts[0].ClosedPorts, "the old TCP service is the same one")
+	require.Len(t, diff.Hosts[0].OpenedPorts, 1)
+	assert.Equal(t, uint16(123), diff.Hosts[0].OpenedPorts[0].TcpPort)
+	require.Len(t, diff.Hosts[0].Services, 1)
+	assert.Equal(t, "0.8.1", diff.Hosts[0].Services[0].OldVersion)
+	assert.Equal(t, "0.8.3", diff.Hosts[0].Services[0].NewVersion)
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strconv"
+
+	"github.com/NikolaB131/nmap-vulners-service/internal/entity"
+	"github.com/NikolaB131/nmap-vulners-service/internal/lookup"
+	"github.com/NikolaB131/nmap-vulners-service/pkg/sl"
+	"github.com/Ullaakut/nmap/v3"
+)
+
+// lookupVulns replaces vulners script output of every port with vulnerabilities found by the resolver in Go,
+// so nmap run results are parsed the same way whichever does the lookup.
+func (v *Vulners) lookupVulns(ctx context.Context, target string, result *nmap.Run) error {
+	for i := range result.Hosts {
+		host := &result.Hosts[i]
+		for j := range host.Ports {
+			port := &host.Ports[j]
+			port.Scripts = slices.DeleteFunc(port.Scripts, func(script nmap.Script) bool {
+				return script.ID == vulnersScriptID
+			})
+			// Same as vulners.nse portrule, nothing can be searched without a detected version
+			if port.State.State != entity.PortStateOpen || port.Service.Version == "" {
+				continue
+			}
+
+			cpes := make([]string, len(port.Service.CPEs))
+			for k, cpe := range port.Service.CPEs {
+				cpes[k] = string(cpe)
+			}
+			matches, err := v.resolver.Resolve(ctx, port.Service.Product, port.Service.Version, cpes)
+			if err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				v.log.Error(
+					"unable to look up vulnerabilities",
+					slog.String("target", target),
+					slog.Int("tcp_port", int(port.ID)),
+					slog.String("product", port.Service.Product),
+					sl.Err(err),
+				)
+				// nmap reports failed scripts the same way
+				port.Scripts = append(port.Scripts, nmap.Script{ID: vulnersScriptID, Output: "ERROR: " + err.Error()})
+				continue
+			}
+			if len(matches) > 0 {
+				port.Scripts = append(port.Scripts, vulnersScript(matches))
+			}
+		}
+	}
+	return nil
+}
+
+// vulnersScript builds the same output as vulners.nse gives in nmap XML.
+func vulnersScript(matches []lookup.Match) nmap.Script {
+	script := nmap.Script{ID: vulnersScriptID}
+	for _, match := range matches {
+		cpeTable := nmap.Table{Key: match.Key}
+		for _, vuln := range match.Vulns {
+			cpeTable.Tables = append(cpeTable.Tables, nmap.Table{Elements: []nmap.Element{
+				{Key: "is_exploit", Value: strconv.FormatBool(vuln.IsExploit)},
+				{Key: "cvss", Value: strconv.FormatFloat(float64(vuln.CvssScore), 'f', -1, 32)},
+				{Key: "id", Value: vuln.Identifier},
+				{Key: "type", Value: vuln.Type},
+			}})
+			// vulners.nse has no url element, it is added for sources linking to other sites than vulners.com
+			if vuln.URL != "" {
+				last := &cpeTable.Tables[len(cpeTable.Tables)-1]
+				last.Elements = append(last.Elements, nmap.Element{Key: "url", Value: vuln.URL})
+			}
+		}
+		script.Tables = append(script.Tables, cpeTable)
+	}
+	return script
+}
@@ -0,0 +1,268 @@
+package service
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NikolaB131/nmap-vulners-service/internal/entity"
+	"github.com/Ullaakut/nmap/v3"
+)
+
+const vulnersScriptID = "vulners"
+
+var (
+	ErrInvalidNmapXML = errors.New("invalid nmap xml")
+)
+
+// ImportXML parses nmap XML output of a scan run elsewhere with vulners script (nmap -sV --script vulners -oX).
+// The returned scan is done but has no ID yet.
+func (v *Vulners) ImportXML(data []byte) (entity.Scan, error) {
+	var result nmap.Run
+	err := nmap.Parse(data, &result)
+	if err != nil {
+		return entity.Scan{}, fmt.Errorf("%w: %s", ErrInvalidNmapXML, err)
+	}
+	if result.Scanner != "nmap" {
+		return entity.Scan{}, fmt.Errorf("%w: nmaprun element not found", ErrInvalidNmapXML)
+	}
+
+	hostsResults, stats, err := v.parseRun(&result)
+	if err != nil {
+		return entity.Scan{}, err
+	}
+	v.enrich(hostsResults)
+
+	scan := entity.Scan{
+		Status:     entity.ScanStatusDone,
+		CreatedAt:  time.Time(result.Start),
+		StartedAt:  time.Time(result.Start),
+		FinishedAt: time.Time(result.Stats.Finished.Time),
+		Results:    hostsResults,
+		Stats:      stats,
+	}
+	for _, host := range hostsResults {
+		scan.Targets = append(scan.Targets, host.TargetIP)
+	}
+	// nmap writes a scaninfo element per scan type, nmap.Run keeps only the last one
+	var infos struct {
+		ScanInfo []nmap.ScanInfo `xml:"scaninfo"`
+	}
+	err = xml.Unmarshal(data, &infos)
+	if err != nil {
+		return entity.Scan{}, fmt.Errorf("%w: %s", ErrInvalidNmapXML, err)
+	}
+	for _, info := range infos.ScanInfo {
+		if info.Services == "" {
+			continue
+		}
+		switch info.Protocol {
+		case "tcp":
+			scan.TcpPorts = append(scan.TcpPorts, strings.Split(info.Services, ",")...)
+		case "udp":
+			scan.UdpPorts = append(scan.UdpPorts, strings.Split(info.Services, ",")...)
+		}
+	}
+	if scan.CreatedAt.IsZero() {
+		scan.CreatedAt = time.Now()
+	}
+
+	v.log.Info("nmap xml imported", slog.Int("hosts", len(hostsResults)), slog.String("nmap_args", result.Args))
+	return scan, nil
+}
+
+// parseRun converts nmap run result into hosts results with every scanned port.
+func (v *Vulners) parseRun(result *nmap.Run) ([]entity.HostResult, entity.ScanStats, error) {
+	hostsResults := make([]entity.HostResult, 0, len(result.Hosts))
+
+	for _, host := range result.Hosts {
+		if len(host.Addresses) == 0 {
+			continue
+		}
+		hostResult := entity.HostResult{
+			TargetIP:  host.Addresses[0].Addr,
+			Status:    host.Status.State,
+			Hostnames: hostnames(host.Hostnames),
+			OS:        osGuess(host.OS),
+		}
+
+		for _, port := range host.Ports {
+			var vulnersScript *nmap.Script
+			for _, script := range port.Scripts {
+				if script.ID == vulnersScriptID {
+					vulnersScript = &script
+				}
+			}
+			service := entity.Service{
+				Name:      port.Service.Name,
+				Product:   port.Service.Product,
+				Version:   port.Service.Version,
+				ExtraInfo: port.Service.ExtraInfo,
+				TcpPort:   port.ID,
+				Protocol:  port.Protocol,
+				State:     port.State.State,
+				CPEs:      serviceCPEs(port.Service, vulnersScript),
+			}
+
+			switch {
+			case vulnersScript != nil && strings.HasPrefix(vulnersScript.Output, "ERROR"):
+				service.ScriptStatus = entity.ScriptStatusError
+			case vulnersScript != nil:
+				service.ScriptStatus = entity.ScriptStatusFound
+			// Same as vulners.nse portrule, nothing can be searched without a detected version
+			case port.State.State != entity.PortStateOpen || port.Service.Version == "":
+				service.ScriptStatus = entity.ScriptStatusSkipped
+			default:
+				service.ScriptStatus = entity.ScriptStatusNotFound
+			}
+			if service.ScriptStatus != entity.ScriptStatusFound {
+				hostResult.Services = append(hostResult.Services, service)
+				continue
+			}
+
+			vulns, warnings := parseVulnersScript(port.ID, port.Protocol, vulnersScript)
+			if len(warnings) > 0 {
+				for _, warning := range warnings {
+					v.log.Warn(
+						"malformed vulners script entry",
+						slog.String("target", hostResult.TargetIP),
+						slog.Int("port", int(warning.TcpPort)),
+						slog.String("protocol", warning.Protocol),
+						slog.String("identifier", warning.Identifier),
+						slog.String("message", warning.Message),
+					)
+				}
+				if v.strictParsing {
+					return nil, entity.ScanStats{}, fmt.Errorf("%w: %s", ErrParseOutput, warnings[0].Message)
+				}
+				hostResult.Warnings = append(hostResult.Warnings, warnings...)
+			}
+			service.Vulns = vulns
+			if len(vulns) == 0 {
+				service.ScriptStatus = entity.ScriptStatusNotFound
+			}
+			hostResult.Services = append(hostResult.Services, service)
+		}
+		hostsResults = append(hostsResults, hostResult)
+	}
+
+	stats := entity.ScanStats{
+		Elapsed:    time.Duration(float64(result.Stats.Finished.Elapsed) * float64(time.Second)),
+		HostsUp:    result.Stats.Hosts.Up,
+		HostsDown:  result.Stats.Hosts.Down,
+		HostsTotal: result.Stats.Hosts.Total,
+	}
+	return hostsResults, stats, nil
+}
+
+// parseVulnersScript collects vulnerabilities from every CPE table of the vulners script output.
+// The same vulnerability is often reported for several CPEs (e.g. nginx:nginx and igor_sysoev:nginx),
+// only its first occurrence is kept. Malformed entries are skipped and reported as warnings.
+func parseVulnersScript(tcpPort uint16, protocol string, vulnersScript *nmap.Script) ([]entity.Vulnerability, []entity.ParseWarning) {
+	var vulns []entity.Vulnerability
+	var warnings []entity.ParseWarning
+	seen := make(map[string]bool)
+
+	for _, cpeTable := range vulnersScript.Tables {
+		for _, vuln := range cpeTable.Tables {
+			vulnerability := entity.Vulnerability{CPE: cpeTable.Key}
+			var warning *entity.ParseWarning
+
+			for _, element := range vuln.Elements {
+				switch element.Key {
+				case "id":
+					vulnerability.Identifier = element.Value
+				case "cvss":
+					cvss, err := strconv.ParseFloat(element.Value, 32)
+					if err != nil {
+						warning = &entity.ParseWarning{Field: element.Key, Value: element.Value, Message: fmt.Sprintf("invalid cvss score: %s", err)}
+						continue
+					}
+					vulnerability.CvssScore = float32(cvss)
+				case "type":
+					vulnerability.Type = element.Value
+				case "is_exploit":
+					vulnerability.IsExploit = element.Value == "true"
+				case "url":
+					vulnerability.URL = element.Value
+				}
+			}
+			if warning == nil && vulnerability.Identifier == "" {
+				warning = &entity.ParseWarning{Field: "id", Message: "vulnerability without identifier"}
+			}
+
+			if warning != nil {
+				warning.TcpPort = tcpPort
+				warning.Protocol = protocol
+				warning.CPE = cpeTable.Key
+				warning.Identifier = vulnerability.Identifier
+				warnings = append(warnings, *warning)
+				continue
+			}
+
+			if seen[vulnerability.Identifier] {
+				continue
+			}
+			seen[vulnerability.Identifier] = true
+
+			if vulnerability.URL == "" {
+				vulnerability.URL = vulnersURL(vulnerability.Type, vulnerability.Identifier)
+			}
+			vulns = append(vulns, vulnerability)
+		}
+	}
+
+	return vulns, warnings
+}
+
+// hostnames returns unique host names, nmap reports the same name given by user and found by reverse DNS twice.
+func hostnames(nmapHostnames []nmap.Hostname) []string {
+	var names []string
+	for _, hostname := range nmapHostnames {
+		if !slices.Contains(names, hostname.Name) {
+			names = append(names, hostname.Name)
+		}
+	}
+	return names
+}
+
+func osGuess(os nmap.OS) entity.OSGuess {
+	var guess entity.OSGuess
+	for _, match := range os.Matches {
+		if match.Accuracy > guess.Accuracy {
+			guess = entity.OSGuess{Name: match.Name, Accuracy: match.Accuracy}
+		}
+	}
+	return guess
+}
+
+// serviceCPEs returns CPEs detected by nmap followed by the ones vulners script additionally checked.
+func serviceCPEs(service nmap.Service, vulnersScript *nmap.Script) []string {
+	var cpes []string
+	for _, cpe := range service.CPEs {
+		cpes = append(cpes, string(cpe))
+	}
+	if vulnersScript == nil {
+		return cpes
+	}
+	for _, cpeTable := range vulnersScript.Tables {
+		// Tables for a software name lookup are keyed with "product version" instead of a CPE
+		if strings.HasPrefix(cpeTable.Key, "cpe:") && !slices.Contains(cpes, cpeTable.Key) {
+			cpes = append(cpes, cpeTable.Key)
+		}
+	}
+	return cpes
+}
+
+// vulnersURL builds the same link to the bulletin as vulners.nse prints in its text output.
+func vulnersURL(vulnType string, identifier string) string {
+	if vulnType == "" || identifier == "" {
+		return ""
+	}
+	return fmt.Sprintf("https://vulners.com/%s/%s", vulnType, identifier)
+}
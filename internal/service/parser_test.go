@@ -0,0 +1,84 @@
+package service
+
+import (
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/NikolaB131/nmap-vulners-service/internal/entity"
+	"github.com/Ullaakut/nmap/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// loadRun parses recorded nmap XML output from testdata
+func loadRun(t *testing.T, name string) *nmap.Run {
+	data, err := os.ReadFile("testdata/" + name)
+	require.NoError(t, err)
+	var result nmap.Run
+	require.NoError(t, nmap.Parse(data, &result))
+	return &result
+}
+
+func TestParseRunSkipsMalformedEntries(t *testing.T) {
+	// One valid vulners entry, one with non-numeric cvss and one without id
+	v := &Vulners{log: slog.Default()}
+
+	hostsResults, _, err := v.parseRun(loadRun(t, "malformed-vulners.xml"))
+	require.NoError(t, err)
+	require.Len(t, hostsResults, 1)
+	require.Len(t, hostsResults[0].Services, 1)
+
+	vulns := hostsResults[0].Services[0].Vulns
+	require.Len(t, vulns, 1, "malformed entries are skipped")
+	assert.Equal(t, "CVE-2021-41773", vulns[0].Identifier)
+	assert.InDelta(t, 7.5, vulns[0].CvssScore, 0.001)
+
+	assert.Equal(t, []entity.ParseWarning{
+		{
+			TcpPort:    8080,
+			Protocol:   "tcp",
+			CPE:        "cpe:/a:apache:http_server:2.4.49",
+			Identifier: "CVE-2021-42013",
+			Field:      "cvss",
+			Value:      "N/A",
+			Message:    `invalid cvss score: strconv.ParseFloat: parsing "N/A": invalid syntax`,
+		},
+		{
+			TcpPort:  8080,
+			Protocol: "tcp",
+			CPE:      "cpe:/a:apache:http_server:2.4.49",
+			Field:    "id",
+			Message:  "vulnerability without identifier",
+		},
+	}, hostsResults[0].Warnings)
+}
+
+func TestParseRunStrict(t *testing.T) {
+	v := &Vulners{log: slog.Default(), strictParsing: true}
+
+	_, _, err := v.parseRun(loadRun(t, "malformed-vulners.xml"))
+	assert.ErrorIs(t, err, ErrParseOutput)
+}
+
+func TestImportXMLScanInfo(t *testing.T) {
+	data, err := os.ReadFile("testdata/tcp-udp.xml")
+	require.NoError(t, err)
+	v := &Vulners{log: slog.Default()}
+
+	scan, err := v.ImportXML(data)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"22", "80"}, scan.TcpPorts)
+	assert.Equal(t, []string{"161"}, scan.UdpPorts, "every scaninfo element is taken")
+
+	require.Len(t, scan.Results, 1)
+	assert.Equal(t, []entity.ParseWarning{{
+		TcpPort:    161,
+		Protocol:   "udp",
+		CPE:        "cpe:/a:net-snmp:net-snmp:5.9",
+		Identifier: "CVE-2022-44792",
+		Field:      "cvss",
+		Value:      "N/A",
+		Message:    `invalid cvss score: strconv.ParseFloat: parsing "N/A": invalid syntax`,
+	}}, scan.Results[0].Warnings)
+}
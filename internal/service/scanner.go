@@ -0,0 +1,241 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NikolaB131/nmap-vulners-service/internal/target"
+	"github.com/Ullaakut/nmap/v3"
+)
+
+var (
+	ErrFixtureNotFound    = errors.New("replay fixture not found")
+	ErrPrivilegesRequired = errors.New("udp scan requires the service to run as root or with CAP_NET_RAW capability")
+)
+
+// capNetRaw is the bit of CAP_NET_RAW in Linux capability sets
+const capNetRaw = 13
+
+// Scanner runs nmap with service detection, and vulners script if it does the lookup, against a single target.
+// Warnings are returned even if the scan failed.
+type Scanner interface {
+	Scan(ctx context.Context, target string, options ScanOptions) (*nmap.Run, []string, error)
+	// Check tells whether a scan with options can be run at all, so it fails before being queued.
+	Check(options ScanOptions) error
+}
+
+type ScanOptions struct {
+	TcpPorts []string // nmap port list items like "22" or "1000-2000", empty means nmap defaults unless UdpPorts are set
+	UdpPorts []string // same as TcpPorts, empty means no UDP scan
+	MinCVSS  float32  // passed to vulners script, results are filtered after parsing anyway
+	// Addresses are scanned instead of the target if it is a hostname already resolved by the service,
+	// so nmap does not resolve it again and cannot get addresses which were not checked against the scope.
+	Addresses []string
+}
+
+// NmapScanner runs the nmap binary found in PATH.
+type NmapScanner struct {
+	scriptPath string
+	scriptArgs map[string]string
+}
+
+// NewNmapScanner creates a scanner running vulners script from scriptPath, empty scriptPath means service detection only.
+// Empty apiURL means the script default (vulners.com), empty apiKey is not sent.
+func NewNmapScanner(scriptPath string, apiURL string, apiKey string) *NmapScanner {
+	scriptArgs := make(map[string]string)
+	if apiURL != "" {
+		scriptArgs["vulners.api_url"] = quoteScriptArg(apiURL)
+	}
+	if apiKey != "" {
+		scriptArgs["vulners.api_key"] = quoteScriptArg(apiKey)
+	}
+	return &NmapScanner{scriptPath: scriptPath, scriptArgs: scriptArgs}
+}
+
+func (s *NmapScanner) Scan(ctx context.Context, target string, options ScanOptions) (*nmap.Run, []string, error) {
+	targets := []string{target}
+	if len(options.Addresses) > 0 {
+		targets = options.Addresses
+	}
+	scanner, err := nmap.NewScanner(
+		ctx,
+		nmap.WithTargets(targets...),
+		nmap.WithServiceInfo(),
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to create nmap scanner: %w", err)
+	}
+	if isIPv6(targets[0]) {
+		scanner.AddOptions(nmap.WithIPv6Scanning())
+	}
+	if len(options.UdpPorts) > 0 {
+		// UDP scan needs raw sockets, so TCP ports are scanned with SYN scan then, it needs the same privileges
+		scanner.AddOptions(nmap.WithUDPScan(), nmap.WithPorts(nmapPorts(options)...))
+		if len(options.TcpPorts) > 0 {
+			scanner.AddOptions(nmap.WithSYNScan())
+		}
+		if os.Geteuid() != 0 {
+			scanner.AddOptions(nmap.WithPrivileged()) // nmap checks only uid otherwise, capabilities are not taken into account
+		}
+	} else if len(options.TcpPorts) > 0 {
+		scanner.AddOptions(nmap.WithPorts(options.TcpPorts...))
+	}
+	if s.scriptPath != "" {
+		scanner.AddOptions(nmap.WithScripts(s.scriptPath))
+		scriptArgs := maps.Clone(s.scriptArgs)
+		if options.MinCVSS > 0 {
+			scriptArgs["vulners.mincvss"] = strconv.FormatFloat(float64(options.MinCVSS), 'f', -1, 32)
+		}
+		if len(scriptArgs) > 0 {
+			scanner.AddOptions(nmap.WithScriptArguments(scriptArgs))
+		}
+	}
+
+	result, warnings, err := scanner.Run()
+	return result, *warnings, err
+}
+
+func (s *NmapScanner) Check(options ScanOptions) error {
+	if len(options.UdpPorts) > 0 && !rawSocketsAllowed() {
+		return ErrPrivilegesRequired
+	}
+	return nil
+}
+
+// isIPv6 tells whether nmap must run in IPv6 mode for an IPv6 address or CIDR block.
+func isIPv6(value string) bool {
+	parsed, err := target.Parse(value)
+	return err == nil && parsed.IPv6()
+}
+
+// nmapPorts joins TCP and UDP ports into nmap syntax like "T:22,80,U:53,161".
+func nmapPorts(options ScanOptions) []string {
+	ports := make([]string, 0, len(options.TcpPorts)+len(options.UdpPorts))
+	for i, port := range options.TcpPorts {
+		if i == 0 {
+			port = "T:" + port
+		}
+		ports = append(ports, port)
+	}
+	for i, port := range options.UdpPorts {
+		if i == 0 {
+			port = "U:" + port
+		}
+		ports = append(ports, port)
+	}
+	return ports
+}
+
+// rawSocketsAllowed tells whether nmap started by the service can send raw packets: the service runs as root
+// or has CAP_NET_RAW in its ambient capabilities, which are inherited by nmap process (Linux only).
+func rawSocketsAllowed() bool {
+	if os.Geteuid() == 0 {
+		return true
+	}
+	data, err := os.ReadFile("/proc/self/status")
+	if err != nil {
+		return false
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		value, ok := strings.CutPrefix(line, "CapAmb:")
+		if !ok {
+			continue
+		}
+		capabilities, err := strconv.ParseUint(strings.TrimSpace(value), 16, 64)
+		return err == nil && capabilities&(1<<capNetRaw) != 0
+	}
+	return false
+}
+
+// quoteScriptArg makes nmap treat value literally even if it has characters like "," or "=".
+func quoteScriptArg(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	return `"` + strings.ReplaceAll(value, `"`, `\"`) + `"`
+}
+
+// ReplayScanner returns recorded nmap XML output instead of running nmap, which makes tests hermetic.
+// Output for a target is read from <dir>/<target>.xml ("/" of CIDR targets is replaced with "_"),
+// ports not requested in the scan are removed from it, privileges are not required for UDP. Every scan takes delay to imitate nmap run time.
+type ReplayScanner struct {
+	dir   string
+	delay time.Duration
+}
+
+func NewReplayScanner(dir string, delay time.Duration) *ReplayScanner {
+	return &ReplayScanner{dir: dir, delay: delay}
+}
+
+func (s *ReplayScanner) Scan(ctx context.Context, target string, options ScanOptions) (*nmap.Run, []string, error) {
+	select {
+	case <-time.After(s.delay):
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	}
+
+	// Output recorded for a hostname is preferred, a hostname pinned to addresses is replayed as its first address otherwise
+	path := filepath.Join(s.dir, strings.ReplaceAll(target, "/", "_")+".xml")
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && len(options.Addresses) > 0 {
+		path = filepath.Join(s.dir, options.Addresses[0]+".xml")
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil, fmt.Errorf("%w: %s", ErrFixtureNotFound, path)
+		}
+		return nil, nil, err
+	}
+
+	var result nmap.Run
+	err = nmap.Parse(data, &result)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to parse replay fixture %s: %w", path, err)
+	}
+
+	if len(options.TcpPorts) > 0 || len(options.UdpPorts) > 0 {
+		for i := range result.Hosts {
+			result.Hosts[i].Ports = slices.DeleteFunc(result.Hosts[i].Ports, func(port nmap.Port) bool {
+				if port.Protocol == "udp" {
+					return !portRequested(port.ID, options.UdpPorts)
+				}
+				return !portRequested(port.ID, options.TcpPorts)
+			})
+		}
+	}
+
+	return &result, nil, nil
+}
+
+func (s *ReplayScanner) Check(options ScanOptions) error {
+	return nil
+}
+
+// portRequested checks port against nmap port list items like "22" or "1000-2000".
+func portRequested(port uint16, tcpPorts []string) bool {
+	for _, item := range tcpPorts {
+		low, high, isRange := strings.Cut(item, "-")
+		if !isRange {
+			high = low
+		}
+		lowInt, err := strconv.Atoi(low)
+		if err != nil {
+			continue
+		}
+		highInt, err := strconv.Atoi(high)
+		if err != nil {
+			continue
+		}
+		if int(port) >= lowInt && int(port) <= highInt {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,287 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/NikolaB131/nmap-vulners-service/internal/entity"
+	"github.com/NikolaB131/nmap-vulners-service/internal/repository"
+	"github.com/NikolaB131/nmap-vulners-service/pkg/sl"
+)
+
+var (
+	ErrScanNotFound = errors.New("scan not found")
+	ErrScanFinished = errors.New("scan already finished")
+)
+
+type scanJob struct {
+	scan         entity.Scan
+	err          error
+	onTargetDone TargetDoneFunc
+	cancel       context.CancelFunc
+	done         chan struct{}
+}
+
+// Scans runs vulners checks as background jobs, so clients do not have to keep a connection open until nmap finishes.
+// Finished scans are saved to the repository and stay available there after they are evicted from memory.
+type Scans struct {
+	log       *slog.Logger
+	vulners   *Vulners
+	repo      repository.ScanRepository
+	retention time.Duration
+	running   chan struct{} // semaphore limiting simultaneously running jobs
+
+	mu   sync.Mutex
+	jobs map[string]*scanJob
+}
+
+func NewScansService(logger *slog.Logger, vulners *Vulners, repo repository.ScanRepository, maxRunning int, retention time.Duration) *Scans {
+	return &Scans{
+		log:       logger,
+		vulners:   vulners,
+		repo:      repo,
+		retention: retention,
+		running:   make(chan struct{}, maxRunning),
+		jobs:      make(map[string]*scanJob),
+	}
+}
+
+// Start queues a new scan and returns immediately.
+func (s *Scans) Start(targets []string, addresses map[string][]string, tcpPorts []string, udpPorts []string, filter entity.VulnsFilter) (entity.Scan, error) {
+	job, err := s.start(targets, addresses, tcpPorts, udpPorts, filter, nil)
+	if err != nil {
+		return entity.Scan{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return job.snapshot(), nil
+}
+
+// Run starts a scan and waits for it to finish. The scan is cancelled if ctx is done earlier.
+// It is queued with the background scans, so it waits for a free slot while maxRunning scans are running.
+// onTargetDone is optional and receives results of every target as soon as they are ready.
+func (s *Scans) Run(ctx context.Context, targets []string, addresses map[string][]string, tcpPorts []string, udpPorts []string, filter entity.VulnsFilter, onTargetDone TargetDoneFunc) (entity.Scan, error) {
+	job, err := s.start(targets, addresses, tcpPorts, udpPorts, filter, onTargetDone)
+	if err != nil {
+		return entity.Scan{}, err
+	}
+
+	select {
+	case <-job.done:
+	case <-ctx.Done():
+		job.cancel()
+		<-job.done
+		return entity.Scan{}, ctx.Err()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return job.snapshot(), job.err
+}
+
+func (s *Scans) start(targets []string, addresses map[string][]string, tcpPorts []string, udpPorts []string, filter entity.VulnsFilter, onTargetDone TargetDoneFunc) (*scanJob, error) {
+	// Reported before queueing, otherwise the scan would fail only when it runs
+	err := s.vulners.scanner.Check(ScanOptions{TcpPorts: tcpPorts, UdpPorts: udpPorts})
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := newScanID()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	job := &scanJob{
+		scan: entity.Scan{
+			ID:        id,
+			Status:    entity.ScanStatusQueued,
+			Targets:   targets,
+			Addresses: addresses,
+			TcpPorts:  tcpPorts,
+			UdpPorts:  udpPorts,
+			Filter:    filter,
+			CreatedAt: time.Now(),
+		},
+		onTargetDone: onTargetDone,
+		cancel:       cancel,
+		done:         make(chan struct{}),
+	}
+
+	s.mu.Lock()
+	s.jobs[id] = job
+	s.mu.Unlock()
+
+	go s.run(ctx, job)
+
+	s.log.Info("scan queued", slog.String("scan_id", id))
+	return job, nil
+}
+
+// Import saves nmap XML output of a scan run elsewhere to the scans history.
+func (s *Scans) Import(ctx context.Context, data []byte) (entity.Scan, error) {
+	scan, err := s.vulners.ImportXML(data)
+	if err != nil {
+		return entity.Scan{}, err
+	}
+
+	scan.ID, err = newScanID()
+	if err != nil {
+		return entity.Scan{}, err
+	}
+
+	err = s.repo.Save(ctx, scan)
+	if err != nil {
+		s.log.Error("unable to save imported scan to repository", slog.String("scan_id", scan.ID), sl.Err(err))
+		return entity.Scan{}, err
+	}
+
+	s.log.Info("scan imported", slog.String("scan_id", scan.ID))
+	return scan, nil
+}
+
+// Get returns an active or recently finished scan, falling back to the scans history.
+func (s *Scans) Get(ctx context.Context, id string) (entity.Scan, error) {
+	s.mu.Lock()
+	job, ok := s.jobs[id]
+	if ok {
+		defer s.mu.Unlock()
+		return job.snapshot(), nil
+	}
+	s.mu.Unlock()
+
+	scan, err := s.repo.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrScanNotFound) {
+			return entity.Scan{}, ErrScanNotFound
+		}
+		s.log.Error("unable to get scan from repository", slog.String("scan_id", id), sl.Err(err))
+		return entity.Scan{}, err
+	}
+	return scan, nil
+}
+
+// List returns finished scans from the history, newest first.
+func (s *Scans) List(ctx context.Context, filter repository.ScanFilter) ([]entity.Scan, error) {
+	scans, err := s.repo.List(ctx, filter)
+	if err != nil {
+		s.log.Error("unable to list scans from repository", sl.Err(err))
+		return nil, err
+	}
+	return scans, nil
+}
+
+// Cancel stops a queued or running scan, killing its nmap processes.
+func (s *Scans) Cancel(id string) (entity.Scan, error) {
+	s.mu.Lock()
+	job, ok := s.jobs[id]
+	if !ok {
+		s.mu.Unlock()
+		// Scans from the history are always finished
+		_, err := s.Get(context.Background(), id)
+		if err != nil {
+			return entity.Scan{}, err
+		}
+		return entity.Scan{}, ErrScanFinished
+	}
+	if job.scan.Status.Finished() {
+		s.mu.Unlock()
+		return entity.Scan{}, ErrScanFinished
+	}
+	s.mu.Unlock()
+
+	job.cancel()
+	<-job.done
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return job.snapshot(), nil
+}
+
+func (s *Scans) run(ctx context.Context, job *scanJob) {
+	defer close(job.done)
+	defer job.cancel()
+
+	select {
+	case s.running <- struct{}{}:
+		defer func() { <-s.running }()
+	case <-ctx.Done():
+		s.finish(job, nil, entity.ScanStats{}, ctx.Err())
+		return
+	}
+
+	s.mu.Lock()
+	job.scan.Status = entity.ScanStatusRunning
+	job.scan.StartedAt = time.Now()
+	s.mu.Unlock()
+
+	results, stats, err := s.vulners.CheckVulnProgress(ctx, job.scan.Targets, job.scan.Addresses, job.scan.TcpPorts, job.scan.UdpPorts, job.scan.Filter, func(target string, hostsResults []entity.HostResult) {
+		s.mu.Lock()
+		job.scan.Results = append(job.scan.Results, hostsResults...)
+		s.mu.Unlock()
+
+		if job.onTargetDone != nil {
+			job.onTargetDone(target, hostsResults)
+		}
+	})
+	s.finish(job, results, stats, err)
+}
+
+func (s *Scans) finish(job *scanJob, results []entity.HostResult, stats entity.ScanStats, err error) {
+	s.mu.Lock()
+
+	job.scan.FinishedAt = time.Now()
+	job.err = err
+	switch {
+	case errors.Is(err, context.Canceled):
+		job.scan.Status = entity.ScanStatusCancelled
+		job.scan.Error = err.Error()
+	case err != nil:
+		job.scan.Status = entity.ScanStatusFailed
+		job.scan.Error = err.Error()
+	default:
+		job.scan.Status = entity.ScanStatusDone
+		job.scan.Results = results
+		job.scan.Stats = stats
+	}
+
+	scan := job.snapshot()
+	s.mu.Unlock()
+
+	s.log.Info("scan finished", slog.String("scan_id", scan.ID), slog.String("status", string(scan.Status)))
+
+	err = s.repo.Save(context.Background(), scan)
+	if err != nil {
+		s.log.Error("unable to save scan to repository", slog.String("scan_id", scan.ID), sl.Err(err))
+	}
+
+	id := scan.ID
+	time.AfterFunc(s.retention, func() {
+		s.mu.Lock()
+		delete(s.jobs, id)
+		s.mu.Unlock()
+	})
+}
+
+// snapshot must be called with Scans.mu held.
+func (j *scanJob) snapshot() entity.Scan {
+	scan := j.scan
+	scan.Results = slices.Clone(j.scan.Results)
+	return scan
+}
+
+func newScanID() (string, error) {
+	b := make([]byte, 16)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
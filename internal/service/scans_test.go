@@ -0,0 +1,31 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/NikolaB131/nmap-vulners-service/internal/entity"
+	"github.com/NikolaB131/nmap-vulners-service/internal/repository/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunWaitsForRunningScans(t *testing.T) {
+	v := NewVulnersService(slog.Default(), fakeScanner{run: loadRun(t, "malformed-vulners.xml")}, nil, nil, time.Minute, 1, false)
+	scans := NewScansService(slog.Default(), v, memory.NewScanRepository(), 1, 0)
+
+	started, err := scans.Start([]string{"hanging"}, nil, nil, nil, entity.VulnsFilter{})
+	require.NoError(t, err)
+	defer scans.Cancel(started.ID)
+	require.Eventually(t, func() bool {
+		scan, err := scans.Get(context.Background(), started.ID)
+		return err == nil && scan.Status == entity.ScanStatusRunning
+	}, time.Second, 10*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err = scans.Run(ctx, []string{"scanned"}, nil, nil, nil, entity.VulnsFilter{}, nil)
+	assert.ErrorIs(t, err, context.DeadlineExceeded, "the only slot is taken by the background scan")
+}
@@ -5,112 +5,192 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
-	"strconv"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/NikolaB131/nmap-vulners-service/internal/entity"
+	"github.com/NikolaB131/nmap-vulners-service/internal/lookup"
 	"github.com/NikolaB131/nmap-vulners-service/pkg/sl"
-	"github.com/Ullaakut/nmap/v3"
+	"golang.org/x/sync/errgroup"
 )
 
 var (
 	ErrScanTimeout = errors.New("scan timeout")
+	ErrParseOutput = errors.New("unable to parse vulners script output")
 )
 
+// Enricher annotates found vulnerabilities in place with data from other sources, like CISA KEV.
+type Enricher interface {
+	Enrich(vulns []entity.Vulnerability)
+}
+
 type Vulners struct {
-	log             *slog.Logger
-	checkTimeout    time.Duration
-	checkScriptPath string
+	log           *slog.Logger
+	scanner       Scanner
+	resolver      *lookup.Resolver // nil means vulners script run by nmap does the lookup
+	enrichers     []Enricher
+	checkTimeout  time.Duration
+	concurrency   int
+	strictParsing bool // fail the whole check on a malformed script entry instead of skipping it
+}
+
+func NewVulnersService(logger *slog.Logger, scanner Scanner, resolver *lookup.Resolver, enrichers []Enricher, checkTimeout time.Duration, concurrency int, strictParsing bool) *Vulners {
+	return &Vulners{
+		log:           logger,
+		scanner:       scanner,
+		resolver:      resolver,
+		enrichers:     enrichers,
+		checkTimeout:  checkTimeout,
+		concurrency:   concurrency,
+		strictParsing: strictParsing,
+	}
 }
 
-func NewVulnersService(logger *slog.Logger, checkTimeout time.Duration, checkScriptPath string) *Vulners {
-	return &Vulners{log: logger, checkTimeout: checkTimeout, checkScriptPath: checkScriptPath}
+// TargetDoneFunc is called as soon as a target scan is finished, possibly from several goroutines at once.
+type TargetDoneFunc func(target string, hostsResults []entity.HostResult)
+
+// CheckVuln scans every target in a separate nmap process, running at most concurrency processes at once.
+// Results are returned in the same order as targets, only vulnerabilities matching filter are kept.
+// addresses maps hostnames of targets to addresses nmap scans instead of them, see ScanOptions.Addresses.
+func (v *Vulners) CheckVuln(ctx context.Context, targets []string, addresses map[string][]string, tcpPorts []string, udpPorts []string, filter entity.VulnsFilter) ([]entity.HostResult, error) {
+	hostsResults, _, err := v.CheckVulnProgress(ctx, targets, addresses, tcpPorts, udpPorts, filter, nil)
+	return hostsResults, err
 }
 
-// TODO: make it faster using async for hosts
-func (v *Vulners) CheckVuln(parentCtx context.Context, targets []string, tcpPorts []string) ([]entity.HostResult, error) {
+// CheckVulnProgress works like CheckVuln and additionally reports every finished target to onTargetDone.
+// Returned stats are summed over all nmap runs, except Elapsed which is the wall time of the whole check.
+// A target which cannot be scanned, e.g. an unresolvable hostname or one not finished in time, is reported
+// as a single result with Error set, other targets are still returned. The whole check fails only if it is
+// canceled, strict parsing fails, or the timeout expires before any target is done.
+func (v *Vulners) CheckVulnProgress(parentCtx context.Context, targets []string, addresses map[string][]string, tcpPorts []string, udpPorts []string, filter entity.VulnsFilter, onTargetDone TargetDoneFunc) ([]entity.HostResult, entity.ScanStats, error) {
 	ctx, cancel := context.WithTimeout(parentCtx, v.checkTimeout)
 	defer cancel()
 
-	scanner, err := nmap.NewScanner(
-		ctx,
-		nmap.WithTargets(targets...),
-		nmap.WithScripts(v.checkScriptPath),
-		nmap.WithServiceInfo(),
-	)
+	startedAt := time.Now()
+	targetsResults := make([][]entity.HostResult, len(targets))
+	targetsStats := make([]entity.ScanStats, len(targets))
+	targetsDone := make([]bool, len(targets))
+
+	var group errgroup.Group
+	group.SetLimit(v.concurrency)
+	for i, target := range targets {
+		group.Go(func() error {
+			hostsResults, stats, err := v.scan(ctx, target, ScanOptions{TcpPorts: tcpPorts, UdpPorts: udpPorts, MinCVSS: filter.MinCVSS, Addresses: addresses[target]}, filter)
+			switch {
+			case err == nil:
+				targetsDone[i] = true
+			case parentCtx.Err() != nil:
+				return parentCtx.Err()
+			case errors.Is(err, ErrParseOutput):
+				cancel() // strict parsing fails the whole check, there is no point to scan the rest
+				return err
+			case ctx.Err() != nil:
+				hostsResults = []entity.HostResult{{TargetIP: target, Error: ErrScanTimeout.Error()}}
+			default:
+				hostsResults = []entity.HostResult{{TargetIP: target, Error: err.Error()}}
+			}
+			targetsResults[i] = hostsResults
+			targetsStats[i] = stats
+			if onTargetDone != nil {
+				onTargetDone(target, hostsResults)
+			}
+			return nil
+		})
+	}
+
+	err := group.Wait()
 	if err != nil {
-		v.log.Error("unable to create nmap scanner", sl.Err(err))
-		return nil, err
+		return nil, entity.ScanStats{}, err
 	}
-	if len(tcpPorts) > 0 {
-		scanner.AddOptions(nmap.WithPorts(tcpPorts...))
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) && !slices.Contains(targetsDone, true) {
+		return nil, entity.ScanStats{}, ErrScanTimeout
 	}
 
-	result, warnings, err := scanner.Run()
-	if len(*warnings) > 0 {
-		for _, warning := range *warnings {
-			v.log.Warn("nmap run finished with warning", slog.String("warning", warning))
-		}
+	var hostsResults []entity.HostResult
+	var stats entity.ScanStats
+	for i, targetResults := range targetsResults {
+		hostsResults = append(hostsResults, targetResults...)
+		stats.HostsUp += targetsStats[i].HostsUp
+		stats.HostsDown += targetsStats[i].HostsDown
+		stats.HostsTotal += targetsStats[i].HostsTotal
+	}
+	stats.Elapsed = time.Since(startedAt)
+
+	v.log.Info(
+		"nmap vulners scan done",
+		slog.String("targets", strings.Join(targets, ", ")),
+		slog.String("tcp_ports", strings.Join(tcpPorts, ", ")),
+		slog.String("udp_ports", strings.Join(udpPorts, ", ")),
+		slog.String("elapsed_time", fmt.Sprintf("%.2f seconds", stats.Elapsed.Seconds())),
+	)
+	return hostsResults, stats, nil
+}
+
+// scan runs a single nmap process for one target, which can still resolve to several hosts (e.g. CIDR).
+func (v *Vulners) scan(ctx context.Context, target string, options ScanOptions, filter entity.VulnsFilter) ([]entity.HostResult, entity.ScanStats, error) {
+	result, warnings, err := v.scanner.Scan(ctx, target, options)
+	for _, warning := range warnings {
+		v.log.Warn("nmap run finished with warning", slog.String("target", target), slog.String("warning", warning))
 	}
 	if err != nil {
-		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
-			return nil, ErrScanTimeout
+		if ctx.Err() != nil {
+			return nil, entity.ScanStats{}, ctx.Err()
 		}
-		v.log.Error("unable to run nmap scan", sl.Err(err))
-		return nil, err
+		v.log.Error("unable to run nmap scan", slog.String("target", target), sl.Err(err))
+		return nil, entity.ScanStats{}, err
 	}
 
-	hostsResults := make([]entity.HostResult, len(result.Hosts))
-
-	for i, host := range result.Hosts {
-		hostResult := entity.HostResult{TargetIP: host.Addresses[0].Addr}
-
-		for _, port := range host.Ports {
-			var vulnersScript *nmap.Script
-			for _, script := range port.Scripts {
-				if script.ID == "vulners" {
-					vulnersScript = &script
-				}
-			}
-			if vulnersScript == nil { // checks vulners script result exists
-				continue
-			}
-			service := entity.Service{
-				Name:    port.Service.Name,
-				Version: port.Service.Version,
-				TcpPort: port.ID,
-				Vulns:   make([]entity.Vulnerability, len(vulnersScript.Tables[0].Tables)),
-			}
+	if v.resolver != nil {
+		err = v.lookupVulns(ctx, target, result)
+		if err != nil {
+			return nil, entity.ScanStats{}, err
+		}
+	}
 
-			for k, vuln := range vulnersScript.Tables[0].Tables {
-				var vulnerability entity.Vulnerability
-
-				for _, element := range vuln.Elements {
-					switch element.Key {
-					case "id":
-						vulnerability.Identifier = element.Value
-					case "cvss":
-						cvss, err := strconv.ParseFloat(element.Value, 32)
-						if err != nil {
-							v.log.Error("unable to parse float from cvss version", sl.Err(err))
-							return nil, err
-						}
-						vulnerability.CvssScore = float32(cvss)
-					}
-				}
-				service.Vulns[k] = vulnerability
+	hostsResults, stats, err := v.parseRun(result)
+	if err != nil {
+		return nil, entity.ScanStats{}, err
+	}
+	if len(options.Addresses) > 0 {
+		// nmap knows only the addresses, the hostname is reported as if nmap had resolved it
+		for i := range hostsResults {
+			if !slices.Contains(hostsResults[i].Hostnames, target) {
+				hostsResults[i].Hostnames = append([]string{target}, hostsResults[i].Hostnames...)
 			}
-			hostResult.Services = append(hostResult.Services, service)
 		}
-		hostsResults[i] = hostResult
 	}
+	v.enrich(hostsResults)
+	filterVulns(hostsResults, filter)
 
-	v.log.Info(
-		"nmap vulners scan done",
-		slog.String("targets", strings.Join(targets, ", ")),
-		slog.String("tcp_ports", strings.Join(tcpPorts, ", ")),
+	v.log.Debug(
+		"nmap target scan done",
+		slog.String("target", target),
 		slog.String("elapsed_time", fmt.Sprintf("%.2f seconds", result.Stats.Finished.Elapsed)),
 	)
-	return hostsResults, nil
+	return hostsResults, stats, nil
+}
+
+func (v *Vulners) enrich(hostsResults []entity.HostResult) {
+	for _, enricher := range v.enrichers {
+		for _, host := range hostsResults {
+			for _, service := range host.Services {
+				enricher.Enrich(service.Vulns)
+			}
+		}
+	}
+}
+
+// filterVulns applies filter to vulnerabilities of every service, services are kept even if none of them is left,
+// their status becomes not_found then.
+func filterVulns(hostsResults []entity.HostResult, filter entity.VulnsFilter) {
+	for i := range hostsResults {
+		for j := range hostsResults[i].Services {
+			service := &hostsResults[i].Services[j]
+			service.Vulns = filter.Apply(service.Vulns)
+			if service.ScriptStatus == entity.ScriptStatusFound && len(service.Vulns) == 0 {
+				service.ScriptStatus = entity.ScriptStatusNotFound
+			}
+		}
+	}
 }
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/NikolaB131/nmap-vulners-service/internal/entity"
+	"github.com/Ullaakut/nmap/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeScanner returns a recorded run for "scanned" targets, fails "unresolvable" ones and blocks on the rest
+type fakeScanner struct {
+	run *nmap.Run
+}
+
+func (s fakeScanner) Scan(ctx context.Context, target string, options ScanOptions) (*nmap.Run, []string, error) {
+	switch target {
+	case "scanned":
+		return s.run, nil, nil
+	case "unresolvable":
+		return nil, nil, nmap.ErrResolveName
+	}
+	<-ctx.Done()
+	return nil, nil, ctx.Err()
+}
+
+func (s fakeScanner) Check(options ScanOptions) error {
+	return nil
+}
+
+func TestCheckVulnKeepsFinishedTargets(t *testing.T) {
+	v := NewVulnersService(slog.Default(), fakeScanner{run: loadRun(t, "malformed-vulners.xml")}, nil, nil, 200*time.Millisecond, 3, false)
+
+	var mu sync.Mutex
+	var done []string
+	hostsResults, _, err := v.CheckVulnProgress(context.Background(), []string{"unresolvable", "scanned", "hanging"}, nil, nil, nil, entity.VulnsFilter{}, func(target string, hostsResults []entity.HostResult) {
+		mu.Lock()
+		defer mu.Unlock()
+		done = append(done, target)
+	})
+	require.NoError(t, err)
+	require.Len(t, hostsResults, 3)
+	assert.Equal(t, entity.HostResult{TargetIP: "unresolvable", Error: nmap.ErrResolveName.Error()}, hostsResults[0])
+	assert.Empty(t, hostsResults[1].Error)
+	assert.Len(t, hostsResults[1].Services, 1)
+	assert.Equal(t, entity.HostResult{TargetIP: "hanging", Error: ErrScanTimeout.Error()}, hostsResults[2])
+	assert.ElementsMatch(t, []string{"unresolvable", "scanned", "hanging"}, done, "failed targets are reported too")
+}
+
+func TestCheckVulnFails(t *testing.T) {
+	v := NewVulnersService(slog.Default(), fakeScanner{}, nil, nil, 100*time.Millisecond, 1, false)
+	_, err := v.CheckVuln(context.Background(), []string{"hanging"}, nil, nil, nil, entity.VulnsFilter{})
+	assert.ErrorIs(t, err, ErrScanTimeout, "nothing is done in time")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = v.CheckVuln(ctx, []string{"hanging"}, nil, nil, nil, entity.VulnsFilter{})
+	assert.ErrorIs(t, err, context.Canceled)
+
+	v = NewVulnersService(slog.Default(), fakeScanner{run: loadRun(t, "malformed-vulners.xml")}, nil, nil, time.Minute, 1, true)
+	_, err = v.CheckVuln(context.Background(), []string{"scanned", "unresolvable"}, nil, nil, nil, entity.VulnsFilter{})
+	assert.ErrorIs(t, err, ErrParseOutput, "strict parsing fails the whole check")
+}
+
+func TestDiffScansSkipsFailedTargets(t *testing.T) {
+	oldScan := entity.Scan{ID: "old", Results: []entity.HostResult{
+		{TargetIP: "127.0.0.1", Services: []entity.Service{{Name: "ssh", TcpPort: 22, Protocol: "tcp"}}},
+		{TargetIP: "127.0.0.2", Hostnames: []string{"localhost2"}, Services: []entity.Service{{Name: "http", TcpPort: 80, Protocol: "tcp"}}},
+	}}
+	newScan := entity.Scan{ID: "new", Results: []entity.HostResult{
+		{TargetIP: "127.0.0.1", Error: ErrScanTimeout.Error()},
+		{TargetIP: "localhost2", Error: "nmap could not resolve a name"},
+	}}
+
+	assert.Empty(t, diffScans(oldScan, newScan).Hosts, "nothing is known about ports of failed targets")
+}
@@ -0,0 +1,184 @@
+package target
+
+import (
+	"fmt"
+	"net/netip"
+)
+
+// Scope limits networks that can be scanned.
+type Scope struct {
+	Allow []netip.Prefix // empty means any address is allowed
+	Deny  []netip.Prefix // takes precedence over Allow
+}
+
+// ClientScope replaces allowed networks of the global scope for clients connecting from Clients,
+// networks denied globally stay denied for them.
+type ClientScope struct {
+	Name    string
+	Clients []netip.Prefix
+	Scope
+}
+
+// Policy chooses the scope of a client by its address.
+type Policy struct {
+	global  Scope
+	clients []ClientScope
+}
+
+func NewPolicy(global Scope, clients []ClientScope) *Policy {
+	return &Policy{global: global, clients: clients}
+}
+
+// ParsePrefixes parses CIDR blocks, a single address is taken as a block of one host.
+func ParsePrefixes(values []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, len(values))
+	for i, value := range values {
+		prefix, err := netip.ParsePrefix(value)
+		if err != nil {
+			addr, addrErr := netip.ParseAddr(value)
+			if addrErr != nil {
+				return nil, fmt.Errorf("invalid CIDR block %q: %w", value, err)
+			}
+			prefix = netip.PrefixFrom(addr, addr.BitLen())
+		}
+		prefixes[i] = unmapPrefix(prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+// Forbidden returns values of targets the client is not allowed to scan. An invalid client address means
+// the client is unknown, the global scope applies to it then.
+func (p *Policy) Forbidden(client netip.Addr, targets []Target) []string {
+	scope := p.global
+	if client.IsValid() {
+		client = client.Unmap()
+		for _, clientScope := range p.clients {
+			if containsAddr(clientScope.Clients, client) {
+				scope = Scope{
+					Allow: clientScope.Allow,
+					Deny:  append(append([]netip.Prefix(nil), p.global.Deny...), clientScope.Deny...),
+				}
+				break
+			}
+		}
+	}
+
+	var forbidden []string
+	for _, target := range targets {
+		if !scope.Allows(target) {
+			forbidden = append(forbidden, target.Value)
+		}
+	}
+	return forbidden
+}
+
+// Allows checks that every address of the target is allowed. Hostnames are checked by their resolved addresses,
+// unresolved ones are allowed only if the scope is not limited at all.
+func (s Scope) Allows(target Target) bool {
+	if target.Kind == KindHostname {
+		if len(target.Addresses) == 0 {
+			return len(s.Allow) == 0 && len(s.Deny) == 0
+		}
+		for _, addr := range target.Addresses {
+			if !s.allowsRange(addr, addr) {
+				return false
+			}
+		}
+		return true
+	}
+
+	first, last, ok := target.bounds()
+	return ok && s.allowsRange(first, last)
+}
+
+// allowsRange checks addresses from first to last. Ranges with gaps, like 10.0.0,2.1, are checked
+// as a continuous one, which can only make the check stricter.
+func (s Scope) allowsRange(first netip.Addr, last netip.Addr) bool {
+	for _, deny := range s.Deny {
+		if deny.Addr().Is4() && first.Is6() {
+			// an IPv6 block wider than ::ffff:0:0/96 still holds IPv4-mapped addresses of the denied network
+			deny = netip.PrefixFrom(netip.AddrFrom16(deny.Addr().As16()), deny.Bits()+96)
+		}
+		if deny.Addr().BitLen() != first.BitLen() {
+			continue
+		}
+		denyFirst, denyLast := prefixBounds(deny)
+		if first.Compare(denyLast) <= 0 && denyFirst.Compare(last) <= 0 {
+			return false
+		}
+	}
+	if len(s.Allow) == 0 {
+		return true
+	}
+	for _, allow := range s.Allow {
+		if allow.Contains(first) && allow.Contains(last) {
+			return true
+		}
+	}
+	return false
+}
+
+// bounds returns the lowest and the highest address of an IP, CIDR or range target.
+func (t Target) bounds() (netip.Addr, netip.Addr, bool) {
+	switch t.Kind {
+	case KindIP:
+		addr := t.Addresses[0].Unmap().WithZone("")
+		return addr, addr, true
+	case KindCIDR:
+		prefix, err := netip.ParsePrefix(t.Value)
+		if err != nil {
+			return netip.Addr{}, netip.Addr{}, false
+		}
+		first, last := prefixBounds(unmapPrefix(prefix.Masked()))
+		return first, last, true
+	case KindRange:
+		return octetRangeBounds(t.Value)
+	}
+	return netip.Addr{}, netip.Addr{}, false
+}
+
+// unmapPrefix turns a block of IPv4-mapped IPv6 addresses like ::ffff:192.0.2.0/120 into the IPv4 one,
+// so it is checked against IPv4 networks of the scope.
+func unmapPrefix(prefix netip.Prefix) netip.Prefix {
+	if prefix.Addr().Is4In6() && prefix.Bits() >= 96 {
+		return netip.PrefixFrom(prefix.Addr().Unmap(), prefix.Bits()-96)
+	}
+	return prefix
+}
+
+func prefixBounds(prefix netip.Prefix) (netip.Addr, netip.Addr) {
+	first := prefix.Masked().Addr()
+	bytes := first.AsSlice()
+	for bit := prefix.Bits(); bit < len(bytes)*8; bit++ {
+		bytes[bit/8] |= 1 << (7 - bit%8)
+	}
+	last, _ := netip.AddrFromSlice(bytes)
+	return first, last
+}
+
+// octetRangeBounds takes the lowest and the highest value of every octet of a range.
+func octetRangeBounds(value string) (netip.Addr, netip.Addr, bool) {
+	octets, err := parseOctetRange(value)
+	if err != nil || len(octets) != 4 {
+		return netip.Addr{}, netip.Addr{}, false
+	}
+	var first, last [4]byte
+	for i, items := range octets {
+		low, high := 255, 0
+		for _, item := range items {
+			low = min(low, item[0])
+			high = max(high, item[1])
+		}
+		first[i], last[i] = byte(low), byte(high)
+	}
+	return netip.AddrFrom4(first), netip.AddrFrom4(last), true
+}
+
+func containsAddr(prefixes []netip.Prefix, addr netip.Addr) bool {
+	for _, prefix := range prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
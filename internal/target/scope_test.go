@@ -0,0 +1,73 @@
+package target
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScopeIPv4MappedTargets(t *testing.T) {
+	deny, err := ParsePrefixes([]string{"192.0.2.0/24"})
+	require.NoError(t, err)
+	scope := Scope{Deny: deny}
+
+	for _, value := range []string{"::ffff:192.0.2.1", "::ffff:192.0.2.0/120", "::ffff:0:0/96", "::/64"} {
+		parsed, err := Parse(value)
+		require.NoError(t, err, value)
+		assert.False(t, scope.Allows(parsed), value)
+	}
+	parsed, err := Parse("::ffff:198.51.100.0/120")
+	require.NoError(t, err)
+	assert.True(t, scope.Allows(parsed), "other IPv4-mapped networks are not denied")
+
+	mapped, err := ParsePrefixes([]string{"::ffff:192.0.2.0/120"})
+	require.NoError(t, err)
+	assert.Equal(t, []netip.Prefix{netip.MustParsePrefix("192.0.2.0/24")}, mapped)
+	parsed, err = Parse("192.0.2.7")
+	require.NoError(t, err)
+	assert.False(t, Scope{Deny: mapped}.Allows(parsed), "IPv4-mapped deny entries apply to IPv4 targets")
+}
+
+func TestPolicy(t *testing.T) {
+	prefixes := func(values ...string) []netip.Prefix {
+		parsed, err := ParsePrefixes(values)
+		require.NoError(t, err)
+		return parsed
+	}
+	parse := func(values ...string) []Target {
+		targets := make([]Target, len(values))
+		for i, value := range values {
+			parsed, err := Parse(value)
+			require.NoError(t, err)
+			targets[i] = parsed
+		}
+		return targets
+	}
+
+	policy := NewPolicy(
+		Scope{Allow: prefixes("10.0.0.0/16", "2001:db8::/32"), Deny: prefixes("10.0.5.0/24", "10.0.0.1")},
+		[]ClientScope{{
+			Name:    "ci",
+			Clients: prefixes("172.16.0.10"),
+			Scope:   Scope{Allow: prefixes("192.168.0.0/16")},
+		}},
+	)
+	someClient := netip.MustParseAddr("172.16.0.20")
+	ciClient := netip.MustParseAddr("172.16.0.10")
+
+	assert.Empty(t, policy.Forbidden(someClient, parse("10.0.1.1", "10.0.2.0/24", "10.0.3-4.1-254", "2001:db8::1")))
+	assert.Equal(t,
+		[]string{"10.1.0.1", "10.0.0.0/8", "10.0.0.1", "10.0.4-6.1", "192.168.1.1", "2001:db9::1"},
+		policy.Forbidden(someClient, parse("10.1.0.1", "10.0.0.0/8", "10.0.0.1", "10.0.4-6.1", "192.168.1.1", "2001:db9::1")),
+		"outside of allowed networks or overlapping denied ones",
+	)
+	assert.Equal(t, []string{"192.168.5.0/24"}, policy.Forbidden(netip.Addr{}, parse("10.0.1.1", "192.168.5.0/24")), "unknown client gets the global scope")
+	assert.Equal(t, []string{"10.0.1.1"}, policy.Forbidden(ciClient, parse("192.168.5.0/24", "10.0.1.1")), "client scope replaces global allow list")
+
+	resolved := Target{Value: "localhost", Kind: KindHostname, Hosts: 1}
+	assert.Equal(t, []string{"localhost"}, policy.Forbidden(someClient, []Target{resolved}), "unresolved hostname cannot be checked")
+	resolved.Addresses = []netip.Addr{netip.MustParseAddr("10.0.1.5")}
+	assert.Empty(t, policy.Forbidden(someClient, []Target{resolved}))
+}
@@ -0,0 +1,273 @@
+// Package target validates scan targets given in nmap syntax: IP addresses, CIDR blocks, octet ranges and hostnames,
+// and counts how many hosts nmap is going to scan for them.
+package target
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Kind string
+
+const (
+	KindIP       Kind = "ip"
+	KindCIDR     Kind = "cidr"
+	KindRange    Kind = "range" // IPv4 octet ranges like 192.168.0-1.1-254
+	KindHostname Kind = "hostname"
+)
+
+// resolveTimeout limits resolution of a single hostname
+const resolveTimeout = 5 * time.Second
+
+var (
+	ErrInvalidTarget = errors.New("invalid target")
+	ErrTooManyHosts  = errors.New("too many hosts")
+)
+
+type Target struct {
+	Value        string // as given
+	Kind         Kind
+	Hosts        uint64       // number of addresses nmap scans, a hostname counts as one, saturates at math.MaxUint64
+	Addresses    []netip.Addr // the address itself or resolved addresses of a hostname, empty for CIDR blocks and ranges
+	ResolveError string       // set if a hostname cannot be resolved, nmap is still run for it
+}
+
+// IPv6 tells whether nmap has to be run in IPv6 mode for the target.
+func (t Target) IPv6() bool {
+	switch t.Kind {
+	case KindIP:
+		return t.Addresses[0].Is6() && !t.Addresses[0].Is4In6()
+	case KindCIDR:
+		prefix, err := netip.ParsePrefix(t.Value)
+		return err == nil && prefix.Addr().Is6()
+	}
+	return false
+}
+
+// ScanAddresses returns addresses nmap has to scan for a resolved hostname instead of resolving it again: IPv4 ones,
+// or IPv6 ones if there are none, as a single nmap run cannot scan both families.
+func (t Target) ScanAddresses() []string {
+	var ipv4, ipv6 []string
+	for _, addr := range t.Addresses {
+		if addr.Is4() {
+			ipv4 = append(ipv4, addr.String())
+		} else {
+			ipv6 = append(ipv6, addr.String())
+		}
+	}
+	if len(ipv4) > 0 {
+		return ipv4
+	}
+	return ipv6
+}
+
+// Parse validates a single target without resolving hostnames.
+func Parse(value string) (Target, error) {
+	target := Target{Value: value}
+	invalid := func(reason string) (Target, error) {
+		return Target{}, fmt.Errorf("%w %q: %s", ErrInvalidTarget, value, reason)
+	}
+
+	if value == "" {
+		return invalid("empty string")
+	}
+	if strings.ContainsAny(value, " \t\r\n") {
+		return invalid("contains whitespace")
+	}
+	if strings.HasPrefix(value, "-") {
+		return invalid("cannot start with a dash") // nmap would take it as an option
+	}
+
+	if addr, err := netip.ParseAddr(value); err == nil {
+		target.Kind = KindIP
+		target.Hosts = 1
+		target.Addresses = []netip.Addr{addr}
+		return target, nil
+	}
+
+	if strings.Contains(value, "/") {
+		prefix, err := netip.ParsePrefix(value)
+		if err != nil {
+			return invalid("malformed CIDR block")
+		}
+		target.Kind = KindCIDR
+		hostBits := prefix.Addr().BitLen() - prefix.Bits()
+		target.Hosts = math.MaxUint64
+		if hostBits < 64 {
+			target.Hosts = 1 << hostBits
+		}
+		return target, nil
+	}
+
+	if isOctetRange(value) {
+		octets, err := parseOctetRange(value)
+		if err != nil {
+			return invalid(err.Error())
+		}
+		target.Kind = KindRange
+		target.Hosts = 1
+		for _, items := range octets {
+			var count uint64
+			for _, item := range items {
+				count += uint64(item[1] - item[0] + 1)
+			}
+			target.Hosts *= count
+		}
+		return target, nil
+	}
+
+	err := validateHostname(value)
+	if err != nil {
+		return invalid(err.Error())
+	}
+	target.Kind = KindHostname
+	target.Hosts = 1
+	return target, nil
+}
+
+// isOctetRange tells whether value looks like an IPv4 address with nmap octet ranges rather than a hostname.
+func isOctetRange(value string) bool {
+	return strings.Count(value, ".") == 3 && strings.Trim(value, "0123456789.-,*") == ""
+}
+
+// parseOctetRange returns low and high values of every item of nmap octet ranges, where every octet
+// is a comma separated list of numbers, ranges like 1-254 and "*" meaning 0-255.
+func parseOctetRange(value string) ([][][2]int, error) {
+	var octets [][][2]int
+	for _, octet := range strings.Split(value, ".") {
+		var items [][2]int
+		for _, item := range strings.Split(octet, ",") {
+			if item == "*" {
+				items = append(items, [2]int{0, 255})
+				continue
+			}
+			low, high, isRange := strings.Cut(item, "-")
+			if !isRange {
+				high = low
+			}
+			lowInt, err := parseOctet(low)
+			if err != nil {
+				return nil, err
+			}
+			highInt, err := parseOctet(high)
+			if err != nil {
+				return nil, err
+			}
+			if lowInt > highInt {
+				return nil, fmt.Errorf("range %s is reversed", item)
+			}
+			items = append(items, [2]int{lowInt, highInt})
+		}
+		octets = append(octets, items)
+	}
+	return octets, nil
+}
+
+func parseOctet(value string) (int, error) {
+	octet, err := strconv.Atoi(value)
+	if err != nil || octet < 0 || octet > 255 {
+		return 0, fmt.Errorf("octet %q is not a number from 0 to 255", value)
+	}
+	return octet, nil
+}
+
+// validateHostname checks RFC 1123 hostname syntax, a trailing dot is allowed.
+func validateHostname(value string) error {
+	name := strings.TrimSuffix(value, ".")
+	if len(name) == 0 || len(name) > 253 {
+		return errors.New("hostname must be 1 to 253 characters long")
+	}
+	labels := strings.Split(name, ".")
+	for _, label := range labels {
+		if len(label) == 0 || len(label) > 63 {
+			return errors.New("hostname labels must be 1 to 63 characters long")
+		}
+		if label[0] == '-' || label[len(label)-1] == '-' {
+			return fmt.Errorf("hostname label %q cannot start or end with a dash", label)
+		}
+		for _, char := range label {
+			if !(char >= 'a' && char <= 'z' || char >= 'A' && char <= 'Z' || char >= '0' && char <= '9' || char == '-') {
+				return fmt.Errorf("hostname has invalid character %q", char)
+			}
+		}
+	}
+	if strings.Trim(labels[len(labels)-1], "0123456789") == "" {
+		return errors.New("not an IP address, and a hostname cannot end with a numeric label")
+	}
+	return nil
+}
+
+// HostnameAddresses pins resolved hostnames to the addresses checked against the scope, otherwise nmap would resolve
+// them again and could get other addresses, e.g. if the DNS record is changed on purpose between the check and the scan.
+func HostnameAddresses(targets []Target) map[string][]string {
+	addresses := make(map[string][]string)
+	for _, t := range targets {
+		if t.Kind == KindHostname && len(t.Addresses) > 0 {
+			addresses[t.Value] = t.ScanAddresses()
+		}
+	}
+	return addresses
+}
+
+// Resolver is implemented by *net.Resolver.
+type Resolver interface {
+	LookupNetIP(ctx context.Context, network string, host string) ([]netip.Addr, error)
+}
+
+// Parser validates all targets of a scan, limits the total number of hosts and resolves hostnames.
+type Parser struct {
+	resolver Resolver
+	maxHosts uint64
+}
+
+func NewParser(resolver Resolver, maxHosts int) *Parser {
+	return &Parser{resolver: resolver, maxHosts: uint64(maxHosts)}
+}
+
+// ParseAll returns targets in the same order as values. Resolution failures do not fail the whole parsing,
+// they are reported in ResolveError of the target.
+func (p *Parser) ParseAll(ctx context.Context, values []string) ([]Target, error) {
+	targets := make([]Target, len(values))
+	var hosts uint64
+	for i, value := range values {
+		target, err := Parse(value)
+		if err != nil {
+			return nil, err
+		}
+		var carry uint64
+		hosts, carry = bits.Add64(hosts, target.Hosts, 0)
+		if carry != 0 || hosts > p.maxHosts {
+			return nil, fmt.Errorf("%w: targets have more than %d hosts", ErrTooManyHosts, p.maxHosts)
+		}
+		targets[i] = target
+	}
+
+	for i := range targets {
+		if targets[i].Kind != KindHostname {
+			continue
+		}
+		resolveCtx, cancel := context.WithTimeout(ctx, resolveTimeout)
+		addresses, err := p.resolver.LookupNetIP(resolveCtx, "ip", targets[i].Value)
+		cancel()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			targets[i].ResolveError = err.Error()
+			continue
+		}
+		for j := range addresses {
+			addresses[j] = addresses[j].Unmap() // IPv4 addresses are returned in IPv6 form
+		}
+		targets[i].Addresses = addresses
+	}
+
+	return targets, nil
+}
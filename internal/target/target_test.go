@@ -0,0 +1,79 @@
+package target
+
+import (
+	"context"
+	"math"
+	"net"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		value string
+		kind  Kind
+		hosts uint64
+		ipv6  bool
+	}{
+		{"192.168.1.10", KindIP, 1, false},
+		{"2001:db8::1", KindIP, 1, true},
+		{"192.168.1.0/24", KindCIDR, 256, false},
+		{"10.0.0.1/32", KindCIDR, 1, false},
+		{"2001:db8::/120", KindCIDR, 256, true},
+		{"2001:db8::/32", KindCIDR, math.MaxUint64, true},
+		{"192.168.1.1-20", KindRange, 20, false},
+		{"192.168.0-1.1,3,5-6", KindRange, 8, false},
+		{"10.0.0.*", KindRange, 256, false},
+		{"localhost", KindHostname, 1, false},
+		{"scanme.nmap.org.", KindHostname, 1, false},
+		{"my-host.example.com", KindHostname, 1, false},
+	}
+	for _, test := range tests {
+		parsed, err := Parse(test.value)
+		require.NoError(t, err, test.value)
+		assert.Equal(t, test.kind, parsed.Kind, test.value)
+		assert.Equal(t, test.hosts, parsed.Hosts, test.value)
+		assert.Equal(t, test.ipv6, parsed.IPv6(), test.value)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, value := range []string{
+		"",
+		"192.168.1.300",
+		"192.168.1.0/33",
+		"192.168.1.20-10",
+		"192.168.1.1-300",
+		"1.2.3",
+		"bad_host.com",
+		"-sS",
+		"host .com",
+		"-leading.com",
+		"example..com",
+	} {
+		_, err := Parse(value)
+		assert.ErrorIs(t, err, ErrInvalidTarget, value)
+	}
+}
+
+func TestParseAll(t *testing.T) {
+	parser := NewParser(net.DefaultResolver, 300)
+
+	targets, err := parser.ParseAll(context.Background(), []string{"10.0.0.0/24", "10.0.1.1-40", "localhost"})
+	require.NoError(t, err)
+	require.Len(t, targets, 3)
+	assert.Contains(t, targets[2].Addresses, netip.MustParseAddr("127.0.0.1"))
+	assert.Empty(t, targets[2].ResolveError)
+
+	_, err = parser.ParseAll(context.Background(), []string{"10.0.0.0/24", "10.0.1.0/26"})
+	assert.ErrorIs(t, err, ErrTooManyHosts)
+	_, err = parser.ParseAll(context.Background(), []string{"2001:db8::/32", "2001:db8::/32"})
+	assert.ErrorIs(t, err, ErrTooManyHosts, "host count must not overflow")
+
+	targets, err = parser.ParseAll(context.Background(), []string{"nonexistent.invalid"})
+	require.NoError(t, err, "resolution failure is reported per target")
+	assert.NotEmpty(t, targets[0].ResolveError)
+}
@@ -0,0 +1,162 @@
+// Package tlsconfig builds server TLS configuration from certificate files, which are reloaded when they change on disk.
+package tlsconfig
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/NikolaB131/nmap-vulners-service/pkg/sl"
+)
+
+var versions = map[string]uint16{
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// ParseVersion converts versions like "1.2" and "1.3" into crypto/tls constants.
+func ParseVersion(value string) (uint16, error) {
+	version, ok := versions[value]
+	if !ok {
+		return 0, fmt.Errorf("unsupported tls version %q, possible values: 1.2, 1.3", value)
+	}
+	return version, nil
+}
+
+// Files of the server certificate, ClientCAFile enables mutual TLS: clients must present a certificate signed by it.
+type Files struct {
+	CertFile     string
+	KeyFile      string
+	ClientCAFile string
+}
+
+// Reloader keeps the certificate and client CAs loaded from Files, every new connection gets the current ones.
+type Reloader struct {
+	log        *slog.Logger
+	files      Files
+	minVersion uint16
+
+	mu          sync.RWMutex
+	certificate *tls.Certificate
+	clientCAs   *x509.CertPool
+	modTimes    map[string]time.Time
+}
+
+func NewReloader(logger *slog.Logger, files Files, minVersion uint16) (*Reloader, error) {
+	r := &Reloader{log: logger, files: files, minVersion: minVersion}
+	err := r.reload()
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// ServerConfig returns configuration for the server, it takes the certificate and client CAs loaded at the moment of handshake.
+func (r *Reloader) ServerConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: r.minVersion,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			r.mu.RLock()
+			defer r.mu.RUnlock()
+
+			config := &tls.Config{
+				MinVersion:   r.minVersion,
+				Certificates: []tls.Certificate{*r.certificate},
+			}
+			if r.clientCAs != nil {
+				config.ClientAuth = tls.RequireAndVerifyClientCert
+				config.ClientCAs = r.clientCAs
+			}
+			return config, nil
+		},
+	}
+}
+
+// Watch checks files modification time every interval and reloads them if any has changed, until ctx is done.
+// If new files cannot be loaded, e.g. the certificate is replaced but the key is not yet, the previous ones are kept.
+func (r *Reloader) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if !r.changed() {
+			continue
+		}
+		err := r.reload()
+		if err != nil {
+			r.log.Error("unable to reload tls certificates, previous ones are kept", sl.Err(err))
+		}
+	}
+}
+
+func (r *Reloader) changed() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for path, modTime := range r.modTimes {
+		if !modificationTime(path).Equal(modTime) {
+			return true
+		}
+	}
+	return false
+}
+
+// reload loads the files again. Their modification times are recorded even if loading fails,
+// so broken files are not retried and reported every tick, but only after they change again.
+func (r *Reloader) reload() error {
+	modTimes := make(map[string]time.Time, 3)
+	for _, path := range []string{r.files.CertFile, r.files.KeyFile, r.files.ClientCAFile} {
+		if path != "" {
+			modTimes[path] = modificationTime(path)
+		}
+	}
+	r.mu.Lock()
+	r.modTimes = modTimes
+	r.mu.Unlock()
+
+	certificate, err := tls.LoadX509KeyPair(r.files.CertFile, r.files.KeyFile)
+	if err != nil {
+		return fmt.Errorf("unable to load tls certificate: %w", err)
+	}
+
+	var clientCAs *x509.CertPool
+	if r.files.ClientCAFile != "" {
+		data, err := os.ReadFile(r.files.ClientCAFile)
+		if err != nil {
+			return fmt.Errorf("unable to read client ca: %w", err)
+		}
+		clientCAs = x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(data) {
+			return errors.New("client ca file has no PEM certificates")
+		}
+	}
+
+	r.mu.Lock()
+	r.certificate = &certificate
+	r.clientCAs = clientCAs
+	r.mu.Unlock()
+
+	r.log.Info("TLS certificates loaded", slog.String("cert_file", r.files.CertFile), slog.Bool("mutual_tls", clientCAs != nil))
+	return nil
+}
+
+// modificationTime returns zero time for files which cannot be stat'ed, e.g. removed while being replaced.
+func modificationTime(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
@@ -0,0 +1,145 @@
+// Package vulnersmock is a local stand-in for the vulners.com burp/software API used by vulners.nse.
+// It answers from JSON fixtures, so scans work in isolated networks, CI and demos.
+package vulnersmock
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/NikolaB131/nmap-vulners-service/pkg/sl"
+)
+
+const SoftwarePath = "/api/v3/burp/software/"
+
+// Fixture is the answer for one software query. Every *.json file of the fixtures directory holds a list of them.
+type Fixture struct {
+	Software string     `json:"software"` // CPE or product name, exactly as vulners.nse sends it
+	Version  string     `json:"version"`
+	Type     string     `json:"type"` // cpe or software
+	Vulns    []Bulletin `json:"vulns"`
+}
+
+type Bulletin struct {
+	ID             string  `json:"id"`
+	Type           string  `json:"type"`
+	CVSS           float64 `json:"cvss"`
+	BulletinFamily string  `json:"bulletinFamily"` // "exploit" marks exploits
+}
+
+type fixtureKey struct {
+	software  string
+	version   string
+	queryType string
+}
+
+type Server struct {
+	log      *slog.Logger
+	apiKey   string
+	fixtures map[fixtureKey][]Bulletin
+	server   *http.Server
+}
+
+// New loads fixtures from dir. If apiKey is not empty, requests without the same X-Api-Key header are rejected.
+func New(logger *slog.Logger, dir string, apiKey string) (*Server, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no fixtures found in %s", dir)
+	}
+
+	fixtures := make(map[fixtureKey][]Bulletin)
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read fixture: %w", err)
+		}
+		var fileFixtures []Fixture
+		err = json.Unmarshal(data, &fileFixtures)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode fixture %s: %w", filepath.Base(path), err)
+		}
+		for _, fixture := range fileFixtures {
+			key := fixtureKey{software: fixture.Software, version: fixture.Version, queryType: fixture.Type}
+			fixtures[key] = append(fixtures[key], fixture.Vulns...)
+		}
+	}
+
+	return &Server{log: logger, apiKey: apiKey, fixtures: fixtures}, nil
+}
+
+// Start listens on addr (e.g. "127.0.0.1:0") in background and returns the API base URL to pass to vulners.nse.
+func (s *Server) Start(addr string) (string, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return "", err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(SoftwarePath, s.handleSoftware)
+	s.server = &http.Server{Handler: mux}
+
+	go func() {
+		err := s.server.Serve(listener)
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.log.Error("vulners mock server stopped", sl.Err(err))
+		}
+	}()
+
+	url := "http://" + listener.Addr().String()
+	s.log.Info("vulners mock server started", slog.String("url", url), slog.Int("fixtures", len(s.fixtures)))
+	return url, nil
+}
+
+func (s *Server) Stop(ctx context.Context) error {
+	if s.server == nil {
+		return nil
+	}
+	return s.server.Shutdown(ctx)
+}
+
+// handleSoftware imitates vulners answers: "OK" with found bulletins or "warning" when nothing is found.
+func (s *Server) handleSoftware(w http.ResponseWriter, r *http.Request) {
+	if s.apiKey != "" && r.Header.Get("X-Api-Key") != s.apiKey {
+		http.Error(w, "invalid api key", http.StatusUnauthorized)
+		return
+	}
+
+	query := r.URL.Query()
+	key := fixtureKey{software: query.Get("software"), version: query.Get("version"), queryType: query.Get("type")}
+	bulletins, ok := s.fixtures[key]
+	s.log.Debug("vulners mock request", slog.String("software", key.software), slog.String("version", key.version), slog.String("type", key.queryType), slog.Bool("found", ok))
+
+	w.Header().Set("Content-Type", "application/json")
+	if !ok {
+		json.NewEncoder(w).Encode(map[string]any{
+			"result": "warning",
+			"data":   map[string]any{"warning": "Nothing found for Burpsuite search request"},
+		})
+		return
+	}
+
+	search := make([]map[string]any, len(bulletins))
+	for i, bulletin := range bulletins {
+		search[i] = map[string]any{
+			"_source": map[string]any{
+				"id":             bulletin.ID,
+				"type":           bulletin.Type,
+				"bulletinFamily": bulletin.BulletinFamily,
+				"cvss":           map[string]any{"score": bulletin.CVSS},
+			},
+		}
+	}
+	json.NewEncoder(w).Encode(map[string]any{
+		"result": "OK",
+		"data":   map[string]any{"search": search},
+	})
+}
@@ -9,6 +9,8 @@ package nmap_vulners_service
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 )
@@ -20,13 +22,133 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type VulnsSort int32
+
+const (
+	VulnsSort_VULNS_SORT_UNSPECIFIED VulnsSort = 0 // as found
+	VulnsSort_VULNS_SORT_CVSS        VulnsSort = 1 // highest CVSS score first
+	VulnsSort_VULNS_SORT_EPSS        VulnsSort = 2 // highest EPSS probability first
+)
+
+// Enum value maps for VulnsSort.
+var (
+	VulnsSort_name = map[int32]string{
+		0: "VULNS_SORT_UNSPECIFIED",
+		1: "VULNS_SORT_CVSS",
+		2: "VULNS_SORT_EPSS",
+	}
+	VulnsSort_value = map[string]int32{
+		"VULNS_SORT_UNSPECIFIED": 0,
+		"VULNS_SORT_CVSS":        1,
+		"VULNS_SORT_EPSS":        2,
+	}
+)
+
+func (x VulnsSort) Enum() *VulnsSort {
+	p := new(VulnsSort)
+	*p = x
+	return p
+}
+
+func (x VulnsSort) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (VulnsSort) Descriptor() protoreflect.EnumDescriptor {
+	return file_pkg_proto_nmap_vulners_service_proto_enumTypes[0].Descriptor()
+}
+
+func (VulnsSort) Type() protoreflect.EnumType {
+	return &file_pkg_proto_nmap_vulners_service_proto_enumTypes[0]
+}
+
+func (x VulnsSort) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use VulnsSort.Descriptor instead.
+func (VulnsSort) EnumDescriptor() ([]byte, []int) {
+	return file_pkg_proto_nmap_vulners_service_proto_rawDescGZIP(), []int{0}
+}
+
+type ScanStatus int32
+
+const (
+	ScanStatus_SCAN_STATUS_UNSPECIFIED ScanStatus = 0
+	ScanStatus_SCAN_STATUS_QUEUED      ScanStatus = 1
+	ScanStatus_SCAN_STATUS_RUNNING     ScanStatus = 2
+	ScanStatus_SCAN_STATUS_DONE        ScanStatus = 3
+	ScanStatus_SCAN_STATUS_FAILED      ScanStatus = 4
+	ScanStatus_SCAN_STATUS_CANCELLED   ScanStatus = 5
+)
+
+// Enum value maps for ScanStatus.
+var (
+	ScanStatus_name = map[int32]string{
+		0: "SCAN_STATUS_UNSPECIFIED",
+		1: "SCAN_STATUS_QUEUED",
+		2: "SCAN_STATUS_RUNNING",
+		3: "SCAN_STATUS_DONE",
+		4: "SCAN_STATUS_FAILED",
+		5: "SCAN_STATUS_CANCELLED",
+	}
+	ScanStatus_value = map[string]int32{
+		"SCAN_STATUS_UNSPECIFIED": 0,
+		"SCAN_STATUS_QUEUED":      1,
+		"SCAN_STATUS_RUNNING":     2,
+		"SCAN_STATUS_DONE":        3,
+		"SCAN_STATUS_FAILED":      4,
+		"SCAN_STATUS_CANCELLED":   5,
+	}
+)
+
+func (x ScanStatus) Enum() *ScanStatus {
+	p := new(ScanStatus)
+	*p = x
+	return p
+}
+
+func (x ScanStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ScanStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_pkg_proto_nmap_vulners_service_proto_enumTypes[1].Descriptor()
+}
+
+func (ScanStatus) Type() protoreflect.EnumType {
+	return &file_pkg_proto_nmap_vulners_service_proto_enumTypes[1]
+}
+
+func (x ScanStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ScanStatus.Descriptor instead.
+func (ScanStatus) EnumDescriptor() ([]byte, []int) {
+	return file_pkg_proto_nmap_vulners_service_proto_rawDescGZIP(), []int{1}
+}
+
 type CheckVulnRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Targets  []string `protobuf:"bytes,1,rep,name=targets,proto3" json:"targets,omitempty"`                           // IP addresses
-	TcpPorts []int32  `protobuf:"varint,2,rep,packed,name=tcp_ports,json=tcpPorts,proto3" json:"tcp_ports,omitempty"` // only TCP ports
+	Targets       []string  `protobuf:"bytes,1,rep,name=targets,proto3" json:"targets,omitempty"`                           // IP addresses, CIDR blocks, nmap IPv4 octet ranges or hostnames
+	TcpPorts      []int32   `protobuf:"varint,2,rep,packed,name=tcp_ports,json=tcpPorts,proto3" json:"tcp_ports,omitempty"` // 1..65535, see tcp_port_ranges
+	OnlyKev       bool      `protobuf:"varint,3,opt,name=only_kev,json=onlyKev,proto3" json:"only_kev,omitempty"`           // report only vulnerabilities from CISA Known Exploited Vulnerabilities catalog
+	MinEpss       float32   `protobuf:"fixed32,4,opt,name=min_epss,json=minEpss,proto3" json:"min_epss,omitempty"`          // report only vulnerabilities with EPSS probability of at least this value, 0..1
+	Sort          VulnsSort `protobuf:"varint,5,opt,name=sort,proto3,enum=VulnsSort" json:"sort,omitempty"`                 // order of vulnerabilities of every service
+	MinCvss       float32   `protobuf:"fixed32,6,opt,name=min_cvss,json=minCvss,proto3" json:"min_cvss,omitempty"`          // report only vulnerabilities with CVSS score of at least this value, exploits without a score are kept
+	OnlyExploits  bool      `protobuf:"varint,7,opt,name=only_exploits,json=onlyExploits,proto3" json:"only_exploits,omitempty"`
+	IncludeTypes  []string  `protobuf:"bytes,8,rep,name=include_types,json=includeTypes,proto3" json:"include_types,omitempty"`        // report only these vulners bulletin types (cve, githubexploit, packetstorm...)
+	ExcludeTypes  []string  `protobuf:"bytes,9,rep,name=exclude_types,json=excludeTypes,proto3" json:"exclude_types,omitempty"`        // do not report these vulners bulletin types
+	MaxPerService int32     `protobuf:"varint,10,opt,name=max_per_service,json=maxPerService,proto3" json:"max_per_service,omitempty"` // report at most this number of vulnerabilities per service after sorting, 0 means no limit
+	UdpPorts      []int32   `protobuf:"varint,11,rep,packed,name=udp_ports,json=udpPorts,proto3" json:"udp_ports,omitempty"`           // UDP scan requires the service to run as root or with CAP_NET_RAW capability
+	// Added to tcp_ports: ranges like "8000-8100" and presets "top-100", "top-1000" (or any other top-N by nmap-services) and "all".
+	// If neither TCP nor UDP ports are given, the default TCP ports of the service config are scanned, nmap default ones if it has none.
+	TcpPortRanges []string `protobuf:"bytes,12,rep,name=tcp_port_ranges,json=tcpPortRanges,proto3" json:"tcp_port_ranges,omitempty"`
+	UdpPortRanges []string `protobuf:"bytes,13,rep,name=udp_port_ranges,json=udpPortRanges,proto3" json:"udp_port_ranges,omitempty"` // added to udp_ports, same syntax as tcp_port_ranges
 }
 
 func (x *CheckVulnRequest) Reset() {
@@ -75,12 +197,90 @@ func (x *CheckVulnRequest) GetTcpPorts() []int32 {
 	return nil
 }
 
+func (x *CheckVulnRequest) GetOnlyKev() bool {
+	if x != nil {
+		return x.OnlyKev
+	}
+	return false
+}
+
+func (x *CheckVulnRequest) GetMinEpss() float32 {
+	if x != nil {
+		return x.MinEpss
+	}
+	return 0
+}
+
+func (x *CheckVulnRequest) GetSort() VulnsSort {
+	if x != nil {
+		return x.Sort
+	}
+	return VulnsSort_VULNS_SORT_UNSPECIFIED
+}
+
+func (x *CheckVulnRequest) GetMinCvss() float32 {
+	if x != nil {
+		return x.MinCvss
+	}
+	return 0
+}
+
+func (x *CheckVulnRequest) GetOnlyExploits() bool {
+	if x != nil {
+		return x.OnlyExploits
+	}
+	return false
+}
+
+func (x *CheckVulnRequest) GetIncludeTypes() []string {
+	if x != nil {
+		return x.IncludeTypes
+	}
+	return nil
+}
+
+func (x *CheckVulnRequest) GetExcludeTypes() []string {
+	if x != nil {
+		return x.ExcludeTypes
+	}
+	return nil
+}
+
+func (x *CheckVulnRequest) GetMaxPerService() int32 {
+	if x != nil {
+		return x.MaxPerService
+	}
+	return 0
+}
+
+func (x *CheckVulnRequest) GetUdpPorts() []int32 {
+	if x != nil {
+		return x.UdpPorts
+	}
+	return nil
+}
+
+func (x *CheckVulnRequest) GetTcpPortRanges() []string {
+	if x != nil {
+		return x.TcpPortRanges
+	}
+	return nil
+}
+
+func (x *CheckVulnRequest) GetUdpPortRanges() []string {
+	if x != nil {
+		return x.UdpPortRanges
+	}
+	return nil
+}
+
 type CheckVulnResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Results []*TargetsResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	Results []*TargetsResult  `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	Targets []*ResolvedTarget `protobuf:"bytes,2,rep,name=targets,proto3" json:"targets,omitempty"` // in the same order as request targets
 }
 
 func (x *CheckVulnResponse) Reset() {
@@ -122,17 +322,27 @@ func (x *CheckVulnResponse) GetResults() []*TargetsResult {
 	return nil
 }
 
-type TargetsResult struct {
+func (x *CheckVulnResponse) GetTargets() []*ResolvedTarget {
+	if x != nil {
+		return x.Targets
+	}
+	return nil
+}
+
+type CheckVulnStreamResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Target   string     `protobuf:"bytes,1,opt,name=target,proto3" json:"target,omitempty"` // target IP
-	Services []*Service `protobuf:"bytes,2,rep,name=services,proto3" json:"services,omitempty"`
+	// Types that are assignable to Payload:
+	//	*CheckVulnStreamResponse_Result
+	//	*CheckVulnStreamResponse_Summary
+	//	*CheckVulnStreamResponse_Targets
+	Payload isCheckVulnStreamResponse_Payload `protobuf_oneof:"payload"`
 }
 
-func (x *TargetsResult) Reset() {
-	*x = TargetsResult{}
+func (x *CheckVulnStreamResponse) Reset() {
+	*x = CheckVulnStreamResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_pkg_proto_nmap_vulners_service_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -140,13 +350,13 @@ func (x *TargetsResult) Reset() {
 	}
 }
 
-func (x *TargetsResult) String() string {
+func (x *CheckVulnStreamResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*TargetsResult) ProtoMessage() {}
+func (*CheckVulnStreamResponse) ProtoMessage() {}
 
-func (x *TargetsResult) ProtoReflect() protoreflect.Message {
+func (x *CheckVulnStreamResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_pkg_proto_nmap_vulners_service_proto_msgTypes[2]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -158,38 +368,71 @@ func (x *TargetsResult) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use TargetsResult.ProtoReflect.Descriptor instead.
-func (*TargetsResult) Descriptor() ([]byte, []int) {
+// Deprecated: Use CheckVulnStreamResponse.ProtoReflect.Descriptor instead.
+func (*CheckVulnStreamResponse) Descriptor() ([]byte, []int) {
 	return file_pkg_proto_nmap_vulners_service_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *TargetsResult) GetTarget() string {
-	if x != nil {
-		return x.Target
+func (m *CheckVulnStreamResponse) GetPayload() isCheckVulnStreamResponse_Payload {
+	if m != nil {
+		return m.Payload
 	}
-	return ""
+	return nil
 }
 
-func (x *TargetsResult) GetServices() []*Service {
-	if x != nil {
-		return x.Services
+func (x *CheckVulnStreamResponse) GetResult() *TargetsResult {
+	if x, ok := x.GetPayload().(*CheckVulnStreamResponse_Result); ok {
+		return x.Result
 	}
 	return nil
 }
 
-type Service struct {
+func (x *CheckVulnStreamResponse) GetSummary() *ScanSummary {
+	if x, ok := x.GetPayload().(*CheckVulnStreamResponse_Summary); ok {
+		return x.Summary
+	}
+	return nil
+}
+
+func (x *CheckVulnStreamResponse) GetTargets() *ResolvedTargets {
+	if x, ok := x.GetPayload().(*CheckVulnStreamResponse_Targets); ok {
+		return x.Targets
+	}
+	return nil
+}
+
+type isCheckVulnStreamResponse_Payload interface {
+	isCheckVulnStreamResponse_Payload()
+}
+
+type CheckVulnStreamResponse_Result struct {
+	Result *TargetsResult `protobuf:"bytes,1,opt,name=result,proto3,oneof"`
+}
+
+type CheckVulnStreamResponse_Summary struct {
+	Summary *ScanSummary `protobuf:"bytes,2,opt,name=summary,proto3,oneof"` // always the last message
+}
+
+type CheckVulnStreamResponse_Targets struct {
+	Targets *ResolvedTargets `protobuf:"bytes,3,opt,name=targets,proto3,oneof"` // always the first message
+}
+
+func (*CheckVulnStreamResponse_Result) isCheckVulnStreamResponse_Payload() {}
+
+func (*CheckVulnStreamResponse_Summary) isCheckVulnStreamResponse_Payload() {}
+
+func (*CheckVulnStreamResponse_Targets) isCheckVulnStreamResponse_Payload() {}
+
+type ResolvedTargets struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Name    string           `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Version string           `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
-	TcpPort int32            `protobuf:"varint,3,opt,name=tcp_port,json=tcpPort,proto3" json:"tcp_port,omitempty"`
-	Vulns   []*Vulnerability `protobuf:"bytes,4,rep,name=vulns,proto3" json:"vulns,omitempty"`
+	Targets []*ResolvedTarget `protobuf:"bytes,1,rep,name=targets,proto3" json:"targets,omitempty"` // in the same order as request targets
 }
 
-func (x *Service) Reset() {
-	*x = Service{}
+func (x *ResolvedTargets) Reset() {
+	*x = ResolvedTargets{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_pkg_proto_nmap_vulners_service_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -197,13 +440,13 @@ func (x *Service) Reset() {
 	}
 }
 
-func (x *Service) String() string {
+func (x *ResolvedTargets) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Service) ProtoMessage() {}
+func (*ResolvedTargets) ProtoMessage() {}
 
-func (x *Service) ProtoReflect() protoreflect.Message {
+func (x *ResolvedTargets) ProtoReflect() protoreflect.Message {
 	mi := &file_pkg_proto_nmap_vulners_service_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -215,65 +458,125 @@ func (x *Service) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Service.ProtoReflect.Descriptor instead.
-func (*Service) Descriptor() ([]byte, []int) {
+// Deprecated: Use ResolvedTargets.ProtoReflect.Descriptor instead.
+func (*ResolvedTargets) Descriptor() ([]byte, []int) {
 	return file_pkg_proto_nmap_vulners_service_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *Service) GetName() string {
+func (x *ResolvedTargets) GetTargets() []*ResolvedTarget {
 	if x != nil {
-		return x.Name
+		return x.Targets
+	}
+	return nil
+}
+
+type ResolvedTarget struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Target       string   `protobuf:"bytes,1,opt,name=target,proto3" json:"target,omitempty"`                                 // as given in the request
+	Kind         string   `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`                                     // ip, cidr, range (nmap IPv4 octet ranges like 192.168.0.1-254) or hostname
+	Hosts        uint64   `protobuf:"varint,3,opt,name=hosts,proto3" json:"hosts,omitempty"`                                  // number of addresses to scan, a hostname counts as one
+	Addresses    []string `protobuf:"bytes,4,rep,name=addresses,proto3" json:"addresses,omitempty"`                           // the address itself or resolved addresses of a hostname, empty for cidr and range
+	ResolveError string   `protobuf:"bytes,5,opt,name=resolve_error,json=resolveError,proto3" json:"resolve_error,omitempty"` // set if a hostname cannot be resolved, it is still passed to nmap
+}
+
+func (x *ResolvedTarget) Reset() {
+	*x = ResolvedTarget{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_nmap_vulners_service_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ResolvedTarget) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolvedTarget) ProtoMessage() {}
+
+func (x *ResolvedTarget) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_nmap_vulners_service_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolvedTarget.ProtoReflect.Descriptor instead.
+func (*ResolvedTarget) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_nmap_vulners_service_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ResolvedTarget) GetTarget() string {
+	if x != nil {
+		return x.Target
 	}
 	return ""
 }
 
-func (x *Service) GetVersion() string {
+func (x *ResolvedTarget) GetKind() string {
 	if x != nil {
-		return x.Version
+		return x.Kind
 	}
 	return ""
 }
 
-func (x *Service) GetTcpPort() int32 {
+func (x *ResolvedTarget) GetHosts() uint64 {
 	if x != nil {
-		return x.TcpPort
+		return x.Hosts
 	}
 	return 0
 }
 
-func (x *Service) GetVulns() []*Vulnerability {
+func (x *ResolvedTarget) GetAddresses() []string {
 	if x != nil {
-		return x.Vulns
+		return x.Addresses
 	}
 	return nil
 }
 
-type Vulnerability struct {
+func (x *ResolvedTarget) GetResolveError() string {
+	if x != nil {
+		return x.ResolveError
+	}
+	return ""
+}
+
+type ScanSummary struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Identifier string  `protobuf:"bytes,1,opt,name=identifier,proto3" json:"identifier,omitempty"`
-	CvssScore  float32 `protobuf:"fixed32,2,opt,name=cvss_score,json=cvssScore,proto3" json:"cvss_score,omitempty"`
+	Elapsed    *durationpb.Duration `protobuf:"bytes,1,opt,name=elapsed,proto3" json:"elapsed,omitempty"` // wall time of the whole check
+	HostsUp    int32                `protobuf:"varint,2,opt,name=hosts_up,json=hostsUp,proto3" json:"hosts_up,omitempty"`
+	HostsDown  int32                `protobuf:"varint,3,opt,name=hosts_down,json=hostsDown,proto3" json:"hosts_down,omitempty"`
+	HostsTotal int32                `protobuf:"varint,4,opt,name=hosts_total,json=hostsTotal,proto3" json:"hosts_total,omitempty"`
 }
 
-func (x *Vulnerability) Reset() {
-	*x = Vulnerability{}
+func (x *ScanSummary) Reset() {
+	*x = ScanSummary{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_pkg_proto_nmap_vulners_service_proto_msgTypes[4]
+		mi := &file_pkg_proto_nmap_vulners_service_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *Vulnerability) String() string {
+func (x *ScanSummary) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Vulnerability) ProtoMessage() {}
+func (*ScanSummary) ProtoMessage() {}
 
-func (x *Vulnerability) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_proto_nmap_vulners_service_proto_msgTypes[4]
+func (x *ScanSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_nmap_vulners_service_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -284,107 +587,1830 @@ func (x *Vulnerability) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Vulnerability.ProtoReflect.Descriptor instead.
-func (*Vulnerability) Descriptor() ([]byte, []int) {
-	return file_pkg_proto_nmap_vulners_service_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use ScanSummary.ProtoReflect.Descriptor instead.
+func (*ScanSummary) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_nmap_vulners_service_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *Vulnerability) GetIdentifier() string {
+func (x *ScanSummary) GetElapsed() *durationpb.Duration {
 	if x != nil {
-		return x.Identifier
+		return x.Elapsed
 	}
-	return ""
+	return nil
 }
 
-func (x *Vulnerability) GetCvssScore() float32 {
+func (x *ScanSummary) GetHostsUp() int32 {
 	if x != nil {
-		return x.CvssScore
+		return x.HostsUp
 	}
 	return 0
 }
 
-var File_pkg_proto_nmap_vulners_service_proto protoreflect.FileDescriptor
+func (x *ScanSummary) GetHostsDown() int32 {
+	if x != nil {
+		return x.HostsDown
+	}
+	return 0
+}
 
-var file_pkg_proto_nmap_vulners_service_proto_rawDesc = []byte{
-	0x0a, 0x24, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x6e, 0x6d, 0x61, 0x70,
-	0x2d, 0x76, 0x75, 0x6c, 0x6e, 0x65, 0x72, 0x73, 0x2d, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
-	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x49, 0x0a, 0x10, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x56,
-	0x75, 0x6c, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x74, 0x61,
-	0x72, 0x67, 0x65, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x74, 0x61, 0x72,
-	0x67, 0x65, 0x74, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x74, 0x63, 0x70, 0x5f, 0x70, 0x6f, 0x72, 0x74,
-	0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x05, 0x52, 0x08, 0x74, 0x63, 0x70, 0x50, 0x6f, 0x72, 0x74,
-	0x73, 0x22, 0x3d, 0x0a, 0x11, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x56, 0x75, 0x6c, 0x6e, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x28, 0x0a, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74,
-	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74,
-	0x73, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73,
-	0x22, 0x4d, 0x0a, 0x0d, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x52, 0x65, 0x73, 0x75, 0x6c,
-	0x74, 0x12, 0x16, 0x0a, 0x06, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x06, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x12, 0x24, 0x0a, 0x08, 0x73, 0x65, 0x72,
-	0x76, 0x69, 0x63, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x08, 0x2e, 0x53, 0x65,
-	0x72, 0x76, 0x69, 0x63, 0x65, 0x52, 0x08, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x22,
-	0x78, 0x0a, 0x07, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61,
-	0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x18,
-	0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x19, 0x0a, 0x08, 0x74, 0x63, 0x70, 0x5f,
-	0x70, 0x6f, 0x72, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x74, 0x63, 0x70, 0x50,
-	0x6f, 0x72, 0x74, 0x12, 0x24, 0x0a, 0x05, 0x76, 0x75, 0x6c, 0x6e, 0x73, 0x18, 0x04, 0x20, 0x03,
-	0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x56, 0x75, 0x6c, 0x6e, 0x65, 0x72, 0x61, 0x62, 0x69, 0x6c, 0x69,
-	0x74, 0x79, 0x52, 0x05, 0x76, 0x75, 0x6c, 0x6e, 0x73, 0x22, 0x4e, 0x0a, 0x0d, 0x56, 0x75, 0x6c,
-	0x6e, 0x65, 0x72, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x12, 0x1e, 0x0a, 0x0a, 0x69, 0x64,
-	0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a,
-	0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x76,
-	0x73, 0x73, 0x5f, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x02, 0x52, 0x09,
-	0x63, 0x76, 0x73, 0x73, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x32, 0x44, 0x0a, 0x0e, 0x4e, 0x65, 0x74,
-	0x56, 0x75, 0x6c, 0x6e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x32, 0x0a, 0x09, 0x43,
-	0x68, 0x65, 0x63, 0x6b, 0x56, 0x75, 0x6c, 0x6e, 0x12, 0x11, 0x2e, 0x43, 0x68, 0x65, 0x63, 0x6b,
-	0x56, 0x75, 0x6c, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x43, 0x68,
-	0x65, 0x63, 0x6b, 0x56, 0x75, 0x6c, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42,
-	0x4b, 0x5a, 0x49, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x4e, 0x69,
-	0x6b, 0x6f, 0x6c, 0x61, 0x42, 0x31, 0x33, 0x31, 0x2f, 0x6e, 0x6d, 0x61, 0x70, 0x2d, 0x76, 0x75,
-	0x6c, 0x6e, 0x65, 0x72, 0x73, 0x2d, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2f, 0x70, 0x6b,
-	0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x6e, 0x6d, 0x61, 0x70, 0x2d, 0x76, 0x75, 0x6c,
-	0x6e, 0x65, 0x72, 0x73, 0x2d, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x62, 0x06, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x33,
+func (x *ScanSummary) GetHostsTotal() int32 {
+	if x != nil {
+		return x.HostsTotal
+	}
+	return 0
 }
 
-var (
-	file_pkg_proto_nmap_vulners_service_proto_rawDescOnce sync.Once
-	file_pkg_proto_nmap_vulners_service_proto_rawDescData = file_pkg_proto_nmap_vulners_service_proto_rawDesc
-)
+type StartScanResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-func file_pkg_proto_nmap_vulners_service_proto_rawDescGZIP() []byte {
-	file_pkg_proto_nmap_vulners_service_proto_rawDescOnce.Do(func() {
-		file_pkg_proto_nmap_vulners_service_proto_rawDescData = protoimpl.X.CompressGZIP(file_pkg_proto_nmap_vulners_service_proto_rawDescData)
-	})
-	return file_pkg_proto_nmap_vulners_service_proto_rawDescData
+	ScanId  string            `protobuf:"bytes,1,opt,name=scan_id,json=scanId,proto3" json:"scan_id,omitempty"`
+	Targets []*ResolvedTarget `protobuf:"bytes,2,rep,name=targets,proto3" json:"targets,omitempty"` // in the same order as request targets
 }
 
-var file_pkg_proto_nmap_vulners_service_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
-var file_pkg_proto_nmap_vulners_service_proto_goTypes = []interface{}{
-	(*CheckVulnRequest)(nil),  // 0: CheckVulnRequest
-	(*CheckVulnResponse)(nil), // 1: CheckVulnResponse
-	(*TargetsResult)(nil),     // 2: TargetsResult
-	(*Service)(nil),           // 3: Service
-	(*Vulnerability)(nil),     // 4: Vulnerability
+func (x *StartScanResponse) Reset() {
+	*x = StartScanResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_nmap_vulners_service_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
-var file_pkg_proto_nmap_vulners_service_proto_depIdxs = []int32{
-	2, // 0: CheckVulnResponse.results:type_name -> TargetsResult
-	3, // 1: TargetsResult.services:type_name -> Service
-	4, // 2: Service.vulns:type_name -> Vulnerability
-	0, // 3: NetVulnService.CheckVuln:input_type -> CheckVulnRequest
-	1, // 4: NetVulnService.CheckVuln:output_type -> CheckVulnResponse
-	4, // [4:5] is the sub-list for method output_type
-	3, // [3:4] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+
+func (x *StartScanResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func init() { file_pkg_proto_nmap_vulners_service_proto_init() }
-func file_pkg_proto_nmap_vulners_service_proto_init() {
-	if File_pkg_proto_nmap_vulners_service_proto != nil {
-		return
-	}
+func (*StartScanResponse) ProtoMessage() {}
+
+func (x *StartScanResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_nmap_vulners_service_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartScanResponse.ProtoReflect.Descriptor instead.
+func (*StartScanResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_nmap_vulners_service_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *StartScanResponse) GetScanId() string {
+	if x != nil {
+		return x.ScanId
+	}
+	return ""
+}
+
+func (x *StartScanResponse) GetTargets() []*ResolvedTarget {
+	if x != nil {
+		return x.Targets
+	}
+	return nil
+}
+
+type GetScanRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ScanId string `protobuf:"bytes,1,opt,name=scan_id,json=scanId,proto3" json:"scan_id,omitempty"`
+}
+
+func (x *GetScanRequest) Reset() {
+	*x = GetScanRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_nmap_vulners_service_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetScanRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetScanRequest) ProtoMessage() {}
+
+func (x *GetScanRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_nmap_vulners_service_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetScanRequest.ProtoReflect.Descriptor instead.
+func (*GetScanRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_nmap_vulners_service_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetScanRequest) GetScanId() string {
+	if x != nil {
+		return x.ScanId
+	}
+	return ""
+}
+
+type GetScanResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Scan *Scan `protobuf:"bytes,1,opt,name=scan,proto3" json:"scan,omitempty"`
+}
+
+func (x *GetScanResponse) Reset() {
+	*x = GetScanResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_nmap_vulners_service_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetScanResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetScanResponse) ProtoMessage() {}
+
+func (x *GetScanResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_nmap_vulners_service_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetScanResponse.ProtoReflect.Descriptor instead.
+func (*GetScanResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_nmap_vulners_service_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetScanResponse) GetScan() *Scan {
+	if x != nil {
+		return x.Scan
+	}
+	return nil
+}
+
+type ListScansRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Target         string                 `protobuf:"bytes,1,opt,name=target,proto3" json:"target,omitempty"`                                        // requested target or scanned host IP, empty means any
+	Since          *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=since,proto3" json:"since,omitempty"`                                          // only scans created after this time
+	Limit          int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`                                         // 0 means no limit
+	IncludeResults bool                   `protobuf:"varint,4,opt,name=include_results,json=includeResults,proto3" json:"include_results,omitempty"` // results are omitted by default to keep the response small
+}
+
+func (x *ListScansRequest) Reset() {
+	*x = ListScansRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_nmap_vulners_service_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListScansRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListScansRequest) ProtoMessage() {}
+
+func (x *ListScansRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_nmap_vulners_service_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListScansRequest.ProtoReflect.Descriptor instead.
+func (*ListScansRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_nmap_vulners_service_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListScansRequest) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+func (x *ListScansRequest) GetSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Since
+	}
+	return nil
+}
+
+func (x *ListScansRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListScansRequest) GetIncludeResults() bool {
+	if x != nil {
+		return x.IncludeResults
+	}
+	return false
+}
+
+type ListScansResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Scans []*Scan `protobuf:"bytes,1,rep,name=scans,proto3" json:"scans,omitempty"` // newest first
+}
+
+func (x *ListScansResponse) Reset() {
+	*x = ListScansResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_nmap_vulners_service_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListScansResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListScansResponse) ProtoMessage() {}
+
+func (x *ListScansResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_nmap_vulners_service_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListScansResponse.ProtoReflect.Descriptor instead.
+func (*ListScansResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_nmap_vulners_service_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListScansResponse) GetScans() []*Scan {
+	if x != nil {
+		return x.Scans
+	}
+	return nil
+}
+
+type DiffScansRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	OldScanId string `protobuf:"bytes,1,opt,name=old_scan_id,json=oldScanId,proto3" json:"old_scan_id,omitempty"`
+	NewScanId string `protobuf:"bytes,2,opt,name=new_scan_id,json=newScanId,proto3" json:"new_scan_id,omitempty"`
+}
+
+func (x *DiffScansRequest) Reset() {
+	*x = DiffScansRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_nmap_vulners_service_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DiffScansRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiffScansRequest) ProtoMessage() {}
+
+func (x *DiffScansRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_nmap_vulners_service_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiffScansRequest.ProtoReflect.Descriptor instead.
+func (*DiffScansRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_nmap_vulners_service_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *DiffScansRequest) GetOldScanId() string {
+	if x != nil {
+		return x.OldScanId
+	}
+	return ""
+}
+
+func (x *DiffScansRequest) GetNewScanId() string {
+	if x != nil {
+		return x.NewScanId
+	}
+	return ""
+}
+
+type DiffScansResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Hosts []*HostDiff `protobuf:"bytes,1,rep,name=hosts,proto3" json:"hosts,omitempty"` // only hosts with changes
+}
+
+func (x *DiffScansResponse) Reset() {
+	*x = DiffScansResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_nmap_vulners_service_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DiffScansResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiffScansResponse) ProtoMessage() {}
+
+func (x *DiffScansResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_nmap_vulners_service_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiffScansResponse.ProtoReflect.Descriptor instead.
+func (*DiffScansResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_nmap_vulners_service_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *DiffScansResponse) GetHosts() []*HostDiff {
+	if x != nil {
+		return x.Hosts
+	}
+	return nil
+}
+
+type HostDiff struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Target          string         `protobuf:"bytes,1,opt,name=target,proto3" json:"target,omitempty"`                              // target IP
+	OpenedPorts     []*Service     `protobuf:"bytes,2,rep,name=opened_ports,json=openedPorts,proto3" json:"opened_ports,omitempty"` // services found only in the new scan
+	ClosedPorts     []*Service     `protobuf:"bytes,3,rep,name=closed_ports,json=closedPorts,proto3" json:"closed_ports,omitempty"` // services found only in the old scan
+	ChangedServices []*ServiceDiff `protobuf:"bytes,4,rep,name=changed_services,json=changedServices,proto3" json:"changed_services,omitempty"`
+}
+
+func (x *HostDiff) Reset() {
+	*x = HostDiff{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_nmap_vulners_service_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *HostDiff) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HostDiff) ProtoMessage() {}
+
+func (x *HostDiff) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_nmap_vulners_service_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HostDiff.ProtoReflect.Descriptor instead.
+func (*HostDiff) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_nmap_vulners_service_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *HostDiff) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+func (x *HostDiff) GetOpenedPorts() []*Service {
+	if x != nil {
+		return x.OpenedPorts
+	}
+	return nil
+}
+
+func (x *HostDiff) GetClosedPorts() []*Service {
+	if x != nil {
+		return x.ClosedPorts
+	}
+	return nil
+}
+
+func (x *HostDiff) GetChangedServices() []*ServiceDiff {
+	if x != nil {
+		return x.ChangedServices
+	}
+	return nil
+}
+
+type ServiceDiff struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Deprecated: Marked as deprecated in pkg/proto/nmap-vulners-service.proto.
+	TcpPort       int32            `protobuf:"varint,1,opt,name=tcp_port,json=tcpPort,proto3" json:"tcp_port,omitempty"` // the same as port, UDP ones too despite the name
+	Name          string           `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	OldVersion    string           `protobuf:"bytes,3,opt,name=old_version,json=oldVersion,proto3" json:"old_version,omitempty"`
+	NewVersion    string           `protobuf:"bytes,4,opt,name=new_version,json=newVersion,proto3" json:"new_version,omitempty"` // differs from old_version only if the version changed
+	NewVulns      []*Vulnerability `protobuf:"bytes,5,rep,name=new_vulns,json=newVulns,proto3" json:"new_vulns,omitempty"`
+	ResolvedVulns []*Vulnerability `protobuf:"bytes,6,rep,name=resolved_vulns,json=resolvedVulns,proto3" json:"resolved_vulns,omitempty"`
+	Protocol      string           `protobuf:"bytes,7,opt,name=protocol,proto3" json:"protocol,omitempty"` // tcp or udp
+	Port          int32            `protobuf:"varint,8,opt,name=port,proto3" json:"port,omitempty"`        // port number, see protocol
+}
+
+func (x *ServiceDiff) Reset() {
+	*x = ServiceDiff{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_nmap_vulners_service_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ServiceDiff) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServiceDiff) ProtoMessage() {}
+
+func (x *ServiceDiff) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_nmap_vulners_service_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServiceDiff.ProtoReflect.Descriptor instead.
+func (*ServiceDiff) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_nmap_vulners_service_proto_rawDescGZIP(), []int{14}
+}
+
+// Deprecated: Marked as deprecated in pkg/proto/nmap-vulners-service.proto.
+func (x *ServiceDiff) GetTcpPort() int32 {
+	if x != nil {
+		return x.TcpPort
+	}
+	return 0
+}
+
+func (x *ServiceDiff) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ServiceDiff) GetOldVersion() string {
+	if x != nil {
+		return x.OldVersion
+	}
+	return ""
+}
+
+func (x *ServiceDiff) GetNewVersion() string {
+	if x != nil {
+		return x.NewVersion
+	}
+	return ""
+}
+
+func (x *ServiceDiff) GetNewVulns() []*Vulnerability {
+	if x != nil {
+		return x.NewVulns
+	}
+	return nil
+}
+
+func (x *ServiceDiff) GetResolvedVulns() []*Vulnerability {
+	if x != nil {
+		return x.ResolvedVulns
+	}
+	return nil
+}
+
+func (x *ServiceDiff) GetProtocol() string {
+	if x != nil {
+		return x.Protocol
+	}
+	return ""
+}
+
+func (x *ServiceDiff) GetPort() int32 {
+	if x != nil {
+		return x.Port
+	}
+	return 0
+}
+
+type ImportNmapXMLRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Xml []byte `protobuf:"bytes,1,opt,name=xml,proto3" json:"xml,omitempty"` // output of nmap -sV --script vulners -oX
+}
+
+func (x *ImportNmapXMLRequest) Reset() {
+	*x = ImportNmapXMLRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_nmap_vulners_service_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ImportNmapXMLRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportNmapXMLRequest) ProtoMessage() {}
+
+func (x *ImportNmapXMLRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_nmap_vulners_service_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportNmapXMLRequest.ProtoReflect.Descriptor instead.
+func (*ImportNmapXMLRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_nmap_vulners_service_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ImportNmapXMLRequest) GetXml() []byte {
+	if x != nil {
+		return x.Xml
+	}
+	return nil
+}
+
+type ImportNmapXMLResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Scan *Scan `protobuf:"bytes,1,opt,name=scan,proto3" json:"scan,omitempty"`
+}
+
+func (x *ImportNmapXMLResponse) Reset() {
+	*x = ImportNmapXMLResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_nmap_vulners_service_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ImportNmapXMLResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportNmapXMLResponse) ProtoMessage() {}
+
+func (x *ImportNmapXMLResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_nmap_vulners_service_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportNmapXMLResponse.ProtoReflect.Descriptor instead.
+func (*ImportNmapXMLResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_nmap_vulners_service_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ImportNmapXMLResponse) GetScan() *Scan {
+	if x != nil {
+		return x.Scan
+	}
+	return nil
+}
+
+type CancelScanRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ScanId string `protobuf:"bytes,1,opt,name=scan_id,json=scanId,proto3" json:"scan_id,omitempty"`
+}
+
+func (x *CancelScanRequest) Reset() {
+	*x = CancelScanRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_nmap_vulners_service_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CancelScanRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelScanRequest) ProtoMessage() {}
+
+func (x *CancelScanRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_nmap_vulners_service_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelScanRequest.ProtoReflect.Descriptor instead.
+func (*CancelScanRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_nmap_vulners_service_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *CancelScanRequest) GetScanId() string {
+	if x != nil {
+		return x.ScanId
+	}
+	return ""
+}
+
+type CancelScanResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Scan *Scan `protobuf:"bytes,1,opt,name=scan,proto3" json:"scan,omitempty"`
+}
+
+func (x *CancelScanResponse) Reset() {
+	*x = CancelScanResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_nmap_vulners_service_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CancelScanResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelScanResponse) ProtoMessage() {}
+
+func (x *CancelScanResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_nmap_vulners_service_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelScanResponse.ProtoReflect.Descriptor instead.
+func (*CancelScanResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_nmap_vulners_service_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *CancelScanResponse) GetScan() *Scan {
+	if x != nil {
+		return x.Scan
+	}
+	return nil
+}
+
+type Scan struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Status        ScanStatus             `protobuf:"varint,2,opt,name=status,proto3,enum=ScanStatus" json:"status,omitempty"`
+	Targets       []string               `protobuf:"bytes,3,rep,name=targets,proto3" json:"targets,omitempty"`
+	TcpPorts      []int32                `protobuf:"varint,4,rep,packed,name=tcp_ports,json=tcpPorts,proto3" json:"tcp_ports,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	StartedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`    // empty while queued
+	FinishedAt    *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=finished_at,json=finishedAt,proto3" json:"finished_at,omitempty"` // empty until finished
+	Error         string                 `protobuf:"bytes,8,opt,name=error,proto3" json:"error,omitempty"`                             // set for failed and cancelled scans
+	Results       []*TargetsResult       `protobuf:"bytes,9,rep,name=results,proto3" json:"results,omitempty"`                         // partial while running
+	Summary       *ScanSummary           `protobuf:"bytes,10,opt,name=summary,proto3" json:"summary,omitempty"`                        // set for done scans
+	UdpPorts      []int32                `protobuf:"varint,11,rep,packed,name=udp_ports,json=udpPorts,proto3" json:"udp_ports,omitempty"`
+	TcpPortRanges []string               `protobuf:"bytes,12,rep,name=tcp_port_ranges,json=tcpPortRanges,proto3" json:"tcp_port_ranges,omitempty"` // all scanned TCP ports, sorted and joined into ranges, tcp_ports has only single ones, both empty mean nmap default ports
+	UdpPortRanges []string               `protobuf:"bytes,13,rep,name=udp_port_ranges,json=udpPortRanges,proto3" json:"udp_port_ranges,omitempty"` // same as tcp_port_ranges for UDP
+}
+
+func (x *Scan) Reset() {
+	*x = Scan{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_nmap_vulners_service_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Scan) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Scan) ProtoMessage() {}
+
+func (x *Scan) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_nmap_vulners_service_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Scan.ProtoReflect.Descriptor instead.
+func (*Scan) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_nmap_vulners_service_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *Scan) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Scan) GetStatus() ScanStatus {
+	if x != nil {
+		return x.Status
+	}
+	return ScanStatus_SCAN_STATUS_UNSPECIFIED
+}
+
+func (x *Scan) GetTargets() []string {
+	if x != nil {
+		return x.Targets
+	}
+	return nil
+}
+
+func (x *Scan) GetTcpPorts() []int32 {
+	if x != nil {
+		return x.TcpPorts
+	}
+	return nil
+}
+
+func (x *Scan) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Scan) GetStartedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartedAt
+	}
+	return nil
+}
+
+func (x *Scan) GetFinishedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FinishedAt
+	}
+	return nil
+}
+
+func (x *Scan) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *Scan) GetResults() []*TargetsResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *Scan) GetSummary() *ScanSummary {
+	if x != nil {
+		return x.Summary
+	}
+	return nil
+}
+
+func (x *Scan) GetUdpPorts() []int32 {
+	if x != nil {
+		return x.UdpPorts
+	}
+	return nil
+}
+
+func (x *Scan) GetTcpPortRanges() []string {
+	if x != nil {
+		return x.TcpPortRanges
+	}
+	return nil
+}
+
+func (x *Scan) GetUdpPortRanges() []string {
+	if x != nil {
+		return x.UdpPortRanges
+	}
+	return nil
+}
+
+type TargetsResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Target        string          `protobuf:"bytes,1,opt,name=target,proto3" json:"target,omitempty"` // target IP, or the target as given in the request if error is set
+	Services      []*Service      `protobuf:"bytes,2,rep,name=services,proto3" json:"services,omitempty"`
+	ParseWarnings []*ParseWarning `protobuf:"bytes,3,rep,name=parse_warnings,json=parseWarnings,proto3" json:"parse_warnings,omitempty"` // malformed vulners script entries skipped while parsing
+	Status        string          `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`                                    // host state: up or down
+	Hostnames     []string        `protobuf:"bytes,5,rep,name=hostnames,proto3" json:"hostnames,omitempty"`                              // given by user and found by reverse DNS
+	Os            *OSGuess        `protobuf:"bytes,6,opt,name=os,proto3" json:"os,omitempty"`                                            // empty if OS detection was not run
+	Error         string          `protobuf:"bytes,7,opt,name=error,proto3" json:"error,omitempty"`                                      // set if the target could not be scanned, e.g. its hostname is not resolved or the check timed out
+}
+
+func (x *TargetsResult) Reset() {
+	*x = TargetsResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_nmap_vulners_service_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TargetsResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TargetsResult) ProtoMessage() {}
+
+func (x *TargetsResult) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_nmap_vulners_service_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TargetsResult.ProtoReflect.Descriptor instead.
+func (*TargetsResult) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_nmap_vulners_service_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *TargetsResult) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+func (x *TargetsResult) GetServices() []*Service {
+	if x != nil {
+		return x.Services
+	}
+	return nil
+}
+
+func (x *TargetsResult) GetParseWarnings() []*ParseWarning {
+	if x != nil {
+		return x.ParseWarnings
+	}
+	return nil
+}
+
+func (x *TargetsResult) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *TargetsResult) GetHostnames() []string {
+	if x != nil {
+		return x.Hostnames
+	}
+	return nil
+}
+
+func (x *TargetsResult) GetOs() *OSGuess {
+	if x != nil {
+		return x.Os
+	}
+	return nil
+}
+
+func (x *TargetsResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type OSGuess struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name     string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Accuracy int32  `protobuf:"varint,2,opt,name=accuracy,proto3" json:"accuracy,omitempty"` // percent
+}
+
+func (x *OSGuess) Reset() {
+	*x = OSGuess{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_nmap_vulners_service_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *OSGuess) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OSGuess) ProtoMessage() {}
+
+func (x *OSGuess) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_nmap_vulners_service_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OSGuess.ProtoReflect.Descriptor instead.
+func (*OSGuess) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_nmap_vulners_service_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *OSGuess) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *OSGuess) GetAccuracy() int32 {
+	if x != nil {
+		return x.Accuracy
+	}
+	return 0
+}
+
+type ParseWarning struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Deprecated: Marked as deprecated in pkg/proto/nmap-vulners-service.proto.
+	TcpPort    int32  `protobuf:"varint,1,opt,name=tcp_port,json=tcpPort,proto3" json:"tcp_port,omitempty"` // the same as port, UDP ones too despite the name
+	Cpe        string `protobuf:"bytes,2,opt,name=cpe,proto3" json:"cpe,omitempty"`
+	Identifier string `protobuf:"bytes,3,opt,name=identifier,proto3" json:"identifier,omitempty"` // empty if the entry has no id
+	Field      string `protobuf:"bytes,4,opt,name=field,proto3" json:"field,omitempty"`           // script output field that failed to parse
+	Value      string `protobuf:"bytes,5,opt,name=value,proto3" json:"value,omitempty"`
+	Message    string `protobuf:"bytes,6,opt,name=message,proto3" json:"message,omitempty"`
+	Protocol   string `protobuf:"bytes,7,opt,name=protocol,proto3" json:"protocol,omitempty"` // tcp or udp
+	Port       int32  `protobuf:"varint,8,opt,name=port,proto3" json:"port,omitempty"`        // port number, see protocol
+}
+
+func (x *ParseWarning) Reset() {
+	*x = ParseWarning{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_nmap_vulners_service_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ParseWarning) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ParseWarning) ProtoMessage() {}
+
+func (x *ParseWarning) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_nmap_vulners_service_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ParseWarning.ProtoReflect.Descriptor instead.
+func (*ParseWarning) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_nmap_vulners_service_proto_rawDescGZIP(), []int{22}
+}
+
+// Deprecated: Marked as deprecated in pkg/proto/nmap-vulners-service.proto.
+func (x *ParseWarning) GetTcpPort() int32 {
+	if x != nil {
+		return x.TcpPort
+	}
+	return 0
+}
+
+func (x *ParseWarning) GetCpe() string {
+	if x != nil {
+		return x.Cpe
+	}
+	return ""
+}
+
+func (x *ParseWarning) GetIdentifier() string {
+	if x != nil {
+		return x.Identifier
+	}
+	return ""
+}
+
+func (x *ParseWarning) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *ParseWarning) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *ParseWarning) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ParseWarning) GetProtocol() string {
+	if x != nil {
+		return x.Protocol
+	}
+	return ""
+}
+
+func (x *ParseWarning) GetPort() int32 {
+	if x != nil {
+		return x.Port
+	}
+	return 0
+}
+
+type Service struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name    string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Version string `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	// Deprecated: Marked as deprecated in pkg/proto/nmap-vulners-service.proto.
+	TcpPort      int32            `protobuf:"varint,3,opt,name=tcp_port,json=tcpPort,proto3" json:"tcp_port,omitempty"` // the same as port, UDP ones too despite the name
+	Vulns        []*Vulnerability `protobuf:"bytes,4,rep,name=vulns,proto3" json:"vulns,omitempty"`
+	Cpes         []string         `protobuf:"bytes,5,rep,name=cpes,proto3" json:"cpes,omitempty"`         // detected by nmap and additionally checked by vulners script
+	Protocol     string           `protobuf:"bytes,6,opt,name=protocol,proto3" json:"protocol,omitempty"` // tcp, udp or sctp
+	State        string           `protobuf:"bytes,7,opt,name=state,proto3" json:"state,omitempty"`       // open, closed, filtered, etc.
+	Product      string           `protobuf:"bytes,8,opt,name=product,proto3" json:"product,omitempty"`
+	ExtraInfo    string           `protobuf:"bytes,9,opt,name=extra_info,json=extraInfo,proto3" json:"extra_info,omitempty"`
+	ScriptStatus string           `protobuf:"bytes,10,opt,name=script_status,json=scriptStatus,proto3" json:"script_status,omitempty"` // found, not_found, skipped (port is not open or version is unknown) or error
+	Port         int32            `protobuf:"varint,11,opt,name=port,proto3" json:"port,omitempty"`                                    // port number, see protocol
+}
+
+func (x *Service) Reset() {
+	*x = Service{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_nmap_vulners_service_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Service) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Service) ProtoMessage() {}
+
+func (x *Service) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_nmap_vulners_service_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Service.ProtoReflect.Descriptor instead.
+func (*Service) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_nmap_vulners_service_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *Service) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Service) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+// Deprecated: Marked as deprecated in pkg/proto/nmap-vulners-service.proto.
+func (x *Service) GetTcpPort() int32 {
+	if x != nil {
+		return x.TcpPort
+	}
+	return 0
+}
+
+func (x *Service) GetVulns() []*Vulnerability {
+	if x != nil {
+		return x.Vulns
+	}
+	return nil
+}
+
+func (x *Service) GetCpes() []string {
+	if x != nil {
+		return x.Cpes
+	}
+	return nil
+}
+
+func (x *Service) GetProtocol() string {
+	if x != nil {
+		return x.Protocol
+	}
+	return ""
+}
+
+func (x *Service) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *Service) GetProduct() string {
+	if x != nil {
+		return x.Product
+	}
+	return ""
+}
+
+func (x *Service) GetExtraInfo() string {
+	if x != nil {
+		return x.ExtraInfo
+	}
+	return ""
+}
+
+func (x *Service) GetScriptStatus() string {
+	if x != nil {
+		return x.ScriptStatus
+	}
+	return ""
+}
+
+func (x *Service) GetPort() int32 {
+	if x != nil {
+		return x.Port
+	}
+	return 0
+}
+
+type Vulnerability struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Identifier     string                 `protobuf:"bytes,1,opt,name=identifier,proto3" json:"identifier,omitempty"`
+	CvssScore      float32                `protobuf:"fixed32,2,opt,name=cvss_score,json=cvssScore,proto3" json:"cvss_score,omitempty"`
+	Type           string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"` // vulners bulletin type: cve, githubexploit, packetstorm...
+	IsExploit      bool                   `protobuf:"varint,4,opt,name=is_exploit,json=isExploit,proto3" json:"is_exploit,omitempty"`
+	Url            string                 `protobuf:"bytes,5,opt,name=url,proto3" json:"url,omitempty"`                                         // link to the bulletin on vulners.com, or to the CVE on nvd.nist.gov for the nvd provider
+	Cpe            string                 `protobuf:"bytes,6,opt,name=cpe,proto3" json:"cpe,omitempty"`                                         // CPE the vulnerability was found for
+	InKev          bool                   `protobuf:"varint,7,opt,name=in_kev,json=inKev,proto3" json:"in_kev,omitempty"`                       // listed in CISA Known Exploited Vulnerabilities catalog
+	KevDateAdded   *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=kev_date_added,json=kevDateAdded,proto3" json:"kev_date_added,omitempty"` // empty if not in_kev
+	Epss           float32                `protobuf:"fixed32,9,opt,name=epss,proto3" json:"epss,omitempty"`                                     // EPSS probability of exploitation in the next 30 days, 0 if unknown
+	EpssPercentile float32                `protobuf:"fixed32,10,opt,name=epss_percentile,json=epssPercentile,proto3" json:"epss_percentile,omitempty"`
+}
+
+func (x *Vulnerability) Reset() {
+	*x = Vulnerability{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_nmap_vulners_service_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Vulnerability) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Vulnerability) ProtoMessage() {}
+
+func (x *Vulnerability) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_nmap_vulners_service_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Vulnerability.ProtoReflect.Descriptor instead.
+func (*Vulnerability) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_nmap_vulners_service_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *Vulnerability) GetIdentifier() string {
+	if x != nil {
+		return x.Identifier
+	}
+	return ""
+}
+
+func (x *Vulnerability) GetCvssScore() float32 {
+	if x != nil {
+		return x.CvssScore
+	}
+	return 0
+}
+
+func (x *Vulnerability) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Vulnerability) GetIsExploit() bool {
+	if x != nil {
+		return x.IsExploit
+	}
+	return false
+}
+
+func (x *Vulnerability) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Vulnerability) GetCpe() string {
+	if x != nil {
+		return x.Cpe
+	}
+	return ""
+}
+
+func (x *Vulnerability) GetInKev() bool {
+	if x != nil {
+		return x.InKev
+	}
+	return false
+}
+
+func (x *Vulnerability) GetKevDateAdded() *timestamppb.Timestamp {
+	if x != nil {
+		return x.KevDateAdded
+	}
+	return nil
+}
+
+func (x *Vulnerability) GetEpss() float32 {
+	if x != nil {
+		return x.Epss
+	}
+	return 0
+}
+
+func (x *Vulnerability) GetEpssPercentile() float32 {
+	if x != nil {
+		return x.EpssPercentile
+	}
+	return 0
+}
+
+var File_pkg_proto_nmap_vulners_service_proto protoreflect.FileDescriptor
+
+var file_pkg_proto_nmap_vulners_service_proto_rawDesc = []byte{
+	0x0a, 0x24, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x6e, 0x6d, 0x61, 0x70,
+	0x2d, 0x76, 0x75, 0x6c, 0x6e, 0x65, 0x72, 0x73, 0x2d, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xbe, 0x03, 0x0a, 0x10, 0x43, 0x68, 0x65, 0x63,
+	0x6b, 0x56, 0x75, 0x6c, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07,
+	0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x74,
+	0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x74, 0x63, 0x70, 0x5f, 0x70, 0x6f,
+	0x72, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x05, 0x52, 0x08, 0x74, 0x63, 0x70, 0x50, 0x6f,
+	0x72, 0x74, 0x73, 0x12, 0x19, 0x0a, 0x08, 0x6f, 0x6e, 0x6c, 0x79, 0x5f, 0x6b, 0x65, 0x76, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x6f, 0x6e, 0x6c, 0x79, 0x4b, 0x65, 0x76, 0x12, 0x19,
+	0x0a, 0x08, 0x6d, 0x69, 0x6e, 0x5f, 0x65, 0x70, 0x73, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x02,
+	0x52, 0x07, 0x6d, 0x69, 0x6e, 0x45, 0x70, 0x73, 0x73, 0x12, 0x1e, 0x0a, 0x04, 0x73, 0x6f, 0x72,
+	0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0a, 0x2e, 0x56, 0x75, 0x6c, 0x6e, 0x73, 0x53,
+	0x6f, 0x72, 0x74, 0x52, 0x04, 0x73, 0x6f, 0x72, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x6d, 0x69, 0x6e,
+	0x5f, 0x63, 0x76, 0x73, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x02, 0x52, 0x07, 0x6d, 0x69, 0x6e,
+	0x43, 0x76, 0x73, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x6f, 0x6e, 0x6c, 0x79, 0x5f, 0x65, 0x78, 0x70,
+	0x6c, 0x6f, 0x69, 0x74, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x6f, 0x6e, 0x6c,
+	0x79, 0x45, 0x78, 0x70, 0x6c, 0x6f, 0x69, 0x74, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x69, 0x6e, 0x63,
+	0x6c, 0x75, 0x64, 0x65, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x0c, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x54, 0x79, 0x70, 0x65, 0x73, 0x12, 0x23,
+	0x0a, 0x0d, 0x65, 0x78, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x73, 0x18,
+	0x09, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0c, 0x65, 0x78, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x54, 0x79,
+	0x70, 0x65, 0x73, 0x12, 0x26, 0x0a, 0x0f, 0x6d, 0x61, 0x78, 0x5f, 0x70, 0x65, 0x72, 0x5f, 0x73,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d, 0x6d, 0x61,
+	0x78, 0x50, 0x65, 0x72, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x75,
+	0x64, 0x70, 0x5f, 0x70, 0x6f, 0x72, 0x74, 0x73, 0x18, 0x0b, 0x20, 0x03, 0x28, 0x05, 0x52, 0x08,
+	0x75, 0x64, 0x70, 0x50, 0x6f, 0x72, 0x74, 0x73, 0x12, 0x26, 0x0a, 0x0f, 0x74, 0x63, 0x70, 0x5f,
+	0x70, 0x6f, 0x72, 0x74, 0x5f, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x18, 0x0c, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x0d, 0x74, 0x63, 0x70, 0x50, 0x6f, 0x72, 0x74, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x73,
+	0x12, 0x26, 0x0a, 0x0f, 0x75, 0x64, 0x70, 0x5f, 0x70, 0x6f, 0x72, 0x74, 0x5f, 0x72, 0x61, 0x6e,
+	0x67, 0x65, 0x73, 0x18, 0x0d, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0d, 0x75, 0x64, 0x70, 0x50, 0x6f,
+	0x72, 0x74, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x22, 0x68, 0x0a, 0x11, 0x43, 0x68, 0x65, 0x63,
+	0x6b, 0x56, 0x75, 0x6c, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x28, 0x0a,
+	0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e,
+	0x2e, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x07,
+	0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x12, 0x29, 0x0a, 0x07, 0x74, 0x61, 0x72, 0x67, 0x65,
+	0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x52, 0x65, 0x73, 0x6f, 0x6c,
+	0x76, 0x65, 0x64, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x52, 0x07, 0x74, 0x61, 0x72, 0x67, 0x65,
+	0x74, 0x73, 0x22, 0xa6, 0x01, 0x0a, 0x17, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x56, 0x75, 0x6c, 0x6e,
+	0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x28,
+	0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e,
+	0x2e, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x48, 0x00,
+	0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x28, 0x0a, 0x07, 0x73, 0x75, 0x6d, 0x6d,
+	0x61, 0x72, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x53, 0x63, 0x61, 0x6e,
+	0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x48, 0x00, 0x52, 0x07, 0x73, 0x75, 0x6d, 0x6d, 0x61,
+	0x72, 0x79, 0x12, 0x2c, 0x0a, 0x07, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x52, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x64, 0x54, 0x61,
+	0x72, 0x67, 0x65, 0x74, 0x73, 0x48, 0x00, 0x52, 0x07, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73,
+	0x42, 0x09, 0x0a, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x22, 0x3c, 0x0a, 0x0f, 0x52,
+	0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x64, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x12, 0x29,
+	0x0a, 0x07, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x0f, 0x2e, 0x52, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x64, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74,
+	0x52, 0x07, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x22, 0x95, 0x01, 0x0a, 0x0e, 0x52, 0x65,
+	0x73, 0x6f, 0x6c, 0x76, 0x65, 0x64, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x12, 0x16, 0x0a, 0x06,
+	0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x74, 0x61,
+	0x72, 0x67, 0x65, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x68, 0x6f, 0x73, 0x74,
+	0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x68, 0x6f, 0x73, 0x74, 0x73, 0x12, 0x1c,
+	0x0a, 0x09, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x09, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x12, 0x23, 0x0a, 0x0d,
+	0x72, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x5f, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0c, 0x72, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x45, 0x72, 0x72, 0x6f,
+	0x72, 0x22, 0x9d, 0x01, 0x0a, 0x0b, 0x53, 0x63, 0x61, 0x6e, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72,
+	0x79, 0x12, 0x33, 0x0a, 0x07, 0x65, 0x6c, 0x61, 0x70, 0x73, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x07, 0x65,
+	0x6c, 0x61, 0x70, 0x73, 0x65, 0x64, 0x12, 0x19, 0x0a, 0x08, 0x68, 0x6f, 0x73, 0x74, 0x73, 0x5f,
+	0x75, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x68, 0x6f, 0x73, 0x74, 0x73, 0x55,
+	0x70, 0x12, 0x1d, 0x0a, 0x0a, 0x68, 0x6f, 0x73, 0x74, 0x73, 0x5f, 0x64, 0x6f, 0x77, 0x6e, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x68, 0x6f, 0x73, 0x74, 0x73, 0x44, 0x6f, 0x77, 0x6e,
+	0x12, 0x1f, 0x0a, 0x0b, 0x68, 0x6f, 0x73, 0x74, 0x73, 0x5f, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x68, 0x6f, 0x73, 0x74, 0x73, 0x54, 0x6f, 0x74, 0x61,
+	0x6c, 0x22, 0x57, 0x0a, 0x11, 0x53, 0x74, 0x61, 0x72, 0x74, 0x53, 0x63, 0x61, 0x6e, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x73, 0x63, 0x61, 0x6e, 0x5f, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x63, 0x61, 0x6e, 0x49, 0x64, 0x12,
+	0x29, 0x0a, 0x07, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x0f, 0x2e, 0x52, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x64, 0x54, 0x61, 0x72, 0x67, 0x65,
+	0x74, 0x52, 0x07, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x22, 0x29, 0x0a, 0x0e, 0x47, 0x65,
+	0x74, 0x53, 0x63, 0x61, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07,
+	0x73, 0x63, 0x61, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73,
+	0x63, 0x61, 0x6e, 0x49, 0x64, 0x22, 0x2c, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x53, 0x63, 0x61, 0x6e,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x19, 0x0a, 0x04, 0x73, 0x63, 0x61, 0x6e,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x05, 0x2e, 0x53, 0x63, 0x61, 0x6e, 0x52, 0x04, 0x73,
+	0x63, 0x61, 0x6e, 0x22, 0x9b, 0x01, 0x0a, 0x10, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x63, 0x61, 0x6e,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x74, 0x61, 0x72, 0x67,
+	0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74,
+	0x12, 0x30, 0x0a, 0x05, 0x73, 0x69, 0x6e, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x05, 0x73, 0x69, 0x6e,
+	0x63, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x27, 0x0a, 0x0f, 0x69, 0x6e, 0x63, 0x6c,
+	0x75, 0x64, 0x65, 0x5f, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x0e, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x73, 0x22, 0x30, 0x0a, 0x11, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x63, 0x61, 0x6e, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1b, 0x0a, 0x05, 0x73, 0x63, 0x61, 0x6e, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x05, 0x2e, 0x53, 0x63, 0x61, 0x6e, 0x52, 0x05, 0x73, 0x63,
+	0x61, 0x6e, 0x73, 0x22, 0x52, 0x0a, 0x10, 0x44, 0x69, 0x66, 0x66, 0x53, 0x63, 0x61, 0x6e, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1e, 0x0a, 0x0b, 0x6f, 0x6c, 0x64, 0x5f, 0x73,
+	0x63, 0x61, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6f, 0x6c,
+	0x64, 0x53, 0x63, 0x61, 0x6e, 0x49, 0x64, 0x12, 0x1e, 0x0a, 0x0b, 0x6e, 0x65, 0x77, 0x5f, 0x73,
+	0x63, 0x61, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6e, 0x65,
+	0x77, 0x53, 0x63, 0x61, 0x6e, 0x49, 0x64, 0x22, 0x34, 0x0a, 0x11, 0x44, 0x69, 0x66, 0x66, 0x53,
+	0x63, 0x61, 0x6e, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1f, 0x0a, 0x05,
+	0x68, 0x6f, 0x73, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x09, 0x2e, 0x48, 0x6f,
+	0x73, 0x74, 0x44, 0x69, 0x66, 0x66, 0x52, 0x05, 0x68, 0x6f, 0x73, 0x74, 0x73, 0x22, 0xb5, 0x01,
+	0x0a, 0x08, 0x48, 0x6f, 0x73, 0x74, 0x44, 0x69, 0x66, 0x66, 0x12, 0x16, 0x0a, 0x06, 0x74, 0x61,
+	0x72, 0x67, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x74, 0x61, 0x72, 0x67,
+	0x65, 0x74, 0x12, 0x2b, 0x0a, 0x0c, 0x6f, 0x70, 0x65, 0x6e, 0x65, 0x64, 0x5f, 0x70, 0x6f, 0x72,
+	0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x08, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x52, 0x0b, 0x6f, 0x70, 0x65, 0x6e, 0x65, 0x64, 0x50, 0x6f, 0x72, 0x74, 0x73, 0x12,
+	0x2b, 0x0a, 0x0c, 0x63, 0x6c, 0x6f, 0x73, 0x65, 0x64, 0x5f, 0x70, 0x6f, 0x72, 0x74, 0x73, 0x18,
+	0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x08, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x52,
+	0x0b, 0x63, 0x6c, 0x6f, 0x73, 0x65, 0x64, 0x50, 0x6f, 0x72, 0x74, 0x73, 0x12, 0x37, 0x0a, 0x10,
+	0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73,
+	0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x44, 0x69, 0x66, 0x66, 0x52, 0x0f, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x53, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x73, 0x22, 0x96, 0x02, 0x0a, 0x0b, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x44, 0x69, 0x66, 0x66, 0x12, 0x1d, 0x0a, 0x08, 0x74, 0x63, 0x70, 0x5f, 0x70, 0x6f, 0x72,
+	0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x42, 0x02, 0x18, 0x01, 0x52, 0x07, 0x74, 0x63, 0x70,
+	0x50, 0x6f, 0x72, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x6f, 0x6c, 0x64, 0x5f,
+	0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6f,
+	0x6c, 0x64, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1f, 0x0a, 0x0b, 0x6e, 0x65, 0x77,
+	0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a,
+	0x6e, 0x65, 0x77, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x2b, 0x0a, 0x09, 0x6e, 0x65,
+	0x77, 0x5f, 0x76, 0x75, 0x6c, 0x6e, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e,
+	0x56, 0x75, 0x6c, 0x6e, 0x65, 0x72, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x52, 0x08, 0x6e,
+	0x65, 0x77, 0x56, 0x75, 0x6c, 0x6e, 0x73, 0x12, 0x35, 0x0a, 0x0e, 0x72, 0x65, 0x73, 0x6f, 0x6c,
+	0x76, 0x65, 0x64, 0x5f, 0x76, 0x75, 0x6c, 0x6e, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x0e, 0x2e, 0x56, 0x75, 0x6c, 0x6e, 0x65, 0x72, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x52,
+	0x0d, 0x72, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x64, 0x56, 0x75, 0x6c, 0x6e, 0x73, 0x12, 0x1a,
+	0x0a, 0x08, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x6f,
+	0x72, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x22, 0x28,
+	0x0a, 0x14, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x4e, 0x6d, 0x61, 0x70, 0x58, 0x4d, 0x4c, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x78, 0x6d, 0x6c, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x03, 0x78, 0x6d, 0x6c, 0x22, 0x32, 0x0a, 0x15, 0x49, 0x6d, 0x70, 0x6f,
+	0x72, 0x74, 0x4e, 0x6d, 0x61, 0x70, 0x58, 0x4d, 0x4c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x19, 0x0a, 0x04, 0x73, 0x63, 0x61, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x05, 0x2e, 0x53, 0x63, 0x61, 0x6e, 0x52, 0x04, 0x73, 0x63, 0x61, 0x6e, 0x22, 0x2c, 0x0a, 0x11,
+	0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x53, 0x63, 0x61, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x17, 0x0a, 0x07, 0x73, 0x63, 0x61, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x73, 0x63, 0x61, 0x6e, 0x49, 0x64, 0x22, 0x2f, 0x0a, 0x12, 0x43, 0x61,
+	0x6e, 0x63, 0x65, 0x6c, 0x53, 0x63, 0x61, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x19, 0x0a, 0x04, 0x73, 0x63, 0x61, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x05,
+	0x2e, 0x53, 0x63, 0x61, 0x6e, 0x52, 0x04, 0x73, 0x63, 0x61, 0x6e, 0x22, 0xfa, 0x03, 0x0a, 0x04,
+	0x53, 0x63, 0x61, 0x6e, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x02, 0x69, 0x64, 0x12, 0x23, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x0b, 0x2e, 0x53, 0x63, 0x61, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x74, 0x61, 0x72,
+	0x67, 0x65, 0x74, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x74, 0x61, 0x72, 0x67,
+	0x65, 0x74, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x74, 0x63, 0x70, 0x5f, 0x70, 0x6f, 0x72, 0x74, 0x73,
+	0x18, 0x04, 0x20, 0x03, 0x28, 0x05, 0x52, 0x08, 0x74, 0x63, 0x70, 0x50, 0x6f, 0x72, 0x74, 0x73,
+	0x12, 0x39, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x39, 0x0a, 0x0a, 0x73,
+	0x74, 0x61, 0x72, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x73, 0x74, 0x61,
+	0x72, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x3b, 0x0a, 0x0b, 0x66, 0x69, 0x6e, 0x69, 0x73, 0x68,
+	0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0a, 0x66, 0x69, 0x6e, 0x69, 0x73, 0x68, 0x65,
+	0x64, 0x41, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x08, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x28, 0x0a, 0x07, 0x72, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x73, 0x18, 0x09, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x54, 0x61, 0x72,
+	0x67, 0x65, 0x74, 0x73, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x07, 0x72, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x73, 0x12, 0x26, 0x0a, 0x07, 0x73, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x18, 0x0a,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x53, 0x63, 0x61, 0x6e, 0x53, 0x75, 0x6d, 0x6d, 0x61,
+	0x72, 0x79, 0x52, 0x07, 0x73, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x12, 0x1b, 0x0a, 0x09, 0x75,
+	0x64, 0x70, 0x5f, 0x70, 0x6f, 0x72, 0x74, 0x73, 0x18, 0x0b, 0x20, 0x03, 0x28, 0x05, 0x52, 0x08,
+	0x75, 0x64, 0x70, 0x50, 0x6f, 0x72, 0x74, 0x73, 0x12, 0x26, 0x0a, 0x0f, 0x74, 0x63, 0x70, 0x5f,
+	0x70, 0x6f, 0x72, 0x74, 0x5f, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x18, 0x0c, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x0d, 0x74, 0x63, 0x70, 0x50, 0x6f, 0x72, 0x74, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x73,
+	0x12, 0x26, 0x0a, 0x0f, 0x75, 0x64, 0x70, 0x5f, 0x70, 0x6f, 0x72, 0x74, 0x5f, 0x72, 0x61, 0x6e,
+	0x67, 0x65, 0x73, 0x18, 0x0d, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0d, 0x75, 0x64, 0x70, 0x50, 0x6f,
+	0x72, 0x74, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x22, 0xe9, 0x01, 0x0a, 0x0d, 0x54, 0x61, 0x72,
+	0x67, 0x65, 0x74, 0x73, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x74, 0x61,
+	0x72, 0x67, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x74, 0x61, 0x72, 0x67,
+	0x65, 0x74, 0x12, 0x24, 0x0a, 0x08, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x18, 0x02,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x08, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x52, 0x08,
+	0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x12, 0x34, 0x0a, 0x0e, 0x70, 0x61, 0x72, 0x73,
+	0x65, 0x5f, 0x77, 0x61, 0x72, 0x6e, 0x69, 0x6e, 0x67, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x0d, 0x2e, 0x50, 0x61, 0x72, 0x73, 0x65, 0x57, 0x61, 0x72, 0x6e, 0x69, 0x6e, 0x67, 0x52,
+	0x0d, 0x70, 0x61, 0x72, 0x73, 0x65, 0x57, 0x61, 0x72, 0x6e, 0x69, 0x6e, 0x67, 0x73, 0x12, 0x16,
+	0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x68, 0x6f, 0x73, 0x74, 0x6e, 0x61,
+	0x6d, 0x65, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x68, 0x6f, 0x73, 0x74, 0x6e,
+	0x61, 0x6d, 0x65, 0x73, 0x12, 0x18, 0x0a, 0x02, 0x6f, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x08, 0x2e, 0x4f, 0x53, 0x47, 0x75, 0x65, 0x73, 0x73, 0x52, 0x02, 0x6f, 0x73, 0x12, 0x14,
+	0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65,
+	0x72, 0x72, 0x6f, 0x72, 0x22, 0x39, 0x0a, 0x07, 0x4f, 0x53, 0x47, 0x75, 0x65, 0x73, 0x73, 0x12,
+	0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x61, 0x63, 0x63, 0x75, 0x72, 0x61, 0x63, 0x79, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x61, 0x63, 0x63, 0x75, 0x72, 0x61, 0x63, 0x79, 0x22,
+	0xd5, 0x01, 0x0a, 0x0c, 0x50, 0x61, 0x72, 0x73, 0x65, 0x57, 0x61, 0x72, 0x6e, 0x69, 0x6e, 0x67,
+	0x12, 0x1d, 0x0a, 0x08, 0x74, 0x63, 0x70, 0x5f, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x05, 0x42, 0x02, 0x18, 0x01, 0x52, 0x07, 0x74, 0x63, 0x70, 0x50, 0x6f, 0x72, 0x74, 0x12,
+	0x10, 0x0a, 0x03, 0x63, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x63, 0x70,
+	0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65,
+	0x72, 0x12, 0x14, 0x0a, 0x05, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x18, 0x0a,
+	0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
+	0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x63, 0x6f, 0x6c, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x63, 0x6f, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x22, 0xb4, 0x02, 0x0a, 0x07, 0x53, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x12, 0x1d, 0x0a, 0x08, 0x74, 0x63, 0x70, 0x5f, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x05, 0x42, 0x02, 0x18, 0x01, 0x52, 0x07, 0x74, 0x63, 0x70, 0x50, 0x6f, 0x72, 0x74,
+	0x12, 0x24, 0x0a, 0x05, 0x76, 0x75, 0x6c, 0x6e, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x0e, 0x2e, 0x56, 0x75, 0x6c, 0x6e, 0x65, 0x72, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x52,
+	0x05, 0x76, 0x75, 0x6c, 0x6e, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x70, 0x65, 0x73, 0x18, 0x05,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x63, 0x70, 0x65, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x18, 0x0a, 0x07,
+	0x70, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x70,
+	0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x65, 0x78, 0x74, 0x72, 0x61, 0x5f,
+	0x69, 0x6e, 0x66, 0x6f, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x65, 0x78, 0x74, 0x72,
+	0x61, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x23, 0x0a, 0x0d, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x5f,
+	0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x73, 0x63,
+	0x72, 0x69, 0x70, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x6f,
+	0x72, 0x74, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x22, 0xbb,
+	0x02, 0x0a, 0x0d, 0x56, 0x75, 0x6c, 0x6e, 0x65, 0x72, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79,
+	0x12, 0x1e, 0x0a, 0x0a, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72,
+	0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x76, 0x73, 0x73, 0x5f, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x02, 0x52, 0x09, 0x63, 0x76, 0x73, 0x73, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x12,
+	0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74,
+	0x79, 0x70, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x69, 0x73, 0x5f, 0x65, 0x78, 0x70, 0x6c, 0x6f, 0x69,
+	0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x69, 0x73, 0x45, 0x78, 0x70, 0x6c, 0x6f,
+	0x69, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x03, 0x75, 0x72, 0x6c, 0x12, 0x10, 0x0a, 0x03, 0x63, 0x70, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x03, 0x63, 0x70, 0x65, 0x12, 0x15, 0x0a, 0x06, 0x69, 0x6e, 0x5f, 0x6b, 0x65, 0x76,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x69, 0x6e, 0x4b, 0x65, 0x76, 0x12, 0x40, 0x0a,
+	0x0e, 0x6b, 0x65, 0x76, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x5f, 0x61, 0x64, 0x64, 0x65, 0x64, 0x18,
+	0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x52, 0x0c, 0x6b, 0x65, 0x76, 0x44, 0x61, 0x74, 0x65, 0x41, 0x64, 0x64, 0x65, 0x64, 0x12,
+	0x12, 0x0a, 0x04, 0x65, 0x70, 0x73, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x02, 0x52, 0x04, 0x65,
+	0x70, 0x73, 0x73, 0x12, 0x27, 0x0a, 0x0f, 0x65, 0x70, 0x73, 0x73, 0x5f, 0x70, 0x65, 0x72, 0x63,
+	0x65, 0x6e, 0x74, 0x69, 0x6c, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x02, 0x52, 0x0e, 0x65, 0x70,
+	0x73, 0x73, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x69, 0x6c, 0x65, 0x2a, 0x51, 0x0a, 0x09,
+	0x56, 0x75, 0x6c, 0x6e, 0x73, 0x53, 0x6f, 0x72, 0x74, 0x12, 0x1a, 0x0a, 0x16, 0x56, 0x55, 0x4c,
+	0x4e, 0x53, 0x5f, 0x53, 0x4f, 0x52, 0x54, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46,
+	0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x13, 0x0a, 0x0f, 0x56, 0x55, 0x4c, 0x4e, 0x53, 0x5f, 0x53,
+	0x4f, 0x52, 0x54, 0x5f, 0x43, 0x56, 0x53, 0x53, 0x10, 0x01, 0x12, 0x13, 0x0a, 0x0f, 0x56, 0x55,
+	0x4c, 0x4e, 0x53, 0x5f, 0x53, 0x4f, 0x52, 0x54, 0x5f, 0x45, 0x50, 0x53, 0x53, 0x10, 0x02, 0x2a,
+	0xa3, 0x01, 0x0a, 0x0a, 0x53, 0x63, 0x61, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1b,
+	0x0a, 0x17, 0x53, 0x43, 0x41, 0x4e, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x55, 0x4e,
+	0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x16, 0x0a, 0x12, 0x53,
+	0x43, 0x41, 0x4e, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x51, 0x55, 0x45, 0x55, 0x45,
+	0x44, 0x10, 0x01, 0x12, 0x17, 0x0a, 0x13, 0x53, 0x43, 0x41, 0x4e, 0x5f, 0x53, 0x54, 0x41, 0x54,
+	0x55, 0x53, 0x5f, 0x52, 0x55, 0x4e, 0x4e, 0x49, 0x4e, 0x47, 0x10, 0x02, 0x12, 0x14, 0x0a, 0x10,
+	0x53, 0x43, 0x41, 0x4e, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x44, 0x4f, 0x4e, 0x45,
+	0x10, 0x03, 0x12, 0x16, 0x0a, 0x12, 0x53, 0x43, 0x41, 0x4e, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55,
+	0x53, 0x5f, 0x46, 0x41, 0x49, 0x4c, 0x45, 0x44, 0x10, 0x04, 0x12, 0x19, 0x0a, 0x15, 0x53, 0x43,
+	0x41, 0x4e, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x43, 0x41, 0x4e, 0x43, 0x45, 0x4c,
+	0x4c, 0x45, 0x44, 0x10, 0x05, 0x32, 0xc7, 0x03, 0x0a, 0x0e, 0x4e, 0x65, 0x74, 0x56, 0x75, 0x6c,
+	0x6e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x32, 0x0a, 0x09, 0x43, 0x68, 0x65, 0x63,
+	0x6b, 0x56, 0x75, 0x6c, 0x6e, 0x12, 0x11, 0x2e, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x56, 0x75, 0x6c,
+	0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x43, 0x68, 0x65, 0x63, 0x6b,
+	0x56, 0x75, 0x6c, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x40, 0x0a, 0x0f,
+	0x43, 0x68, 0x65, 0x63, 0x6b, 0x56, 0x75, 0x6c, 0x6e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12,
+	0x11, 0x2e, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x56, 0x75, 0x6c, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x18, 0x2e, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x56, 0x75, 0x6c, 0x6e, 0x53, 0x74,
+	0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01, 0x12, 0x32,
+	0x0a, 0x09, 0x53, 0x74, 0x61, 0x72, 0x74, 0x53, 0x63, 0x61, 0x6e, 0x12, 0x11, 0x2e, 0x43, 0x68,
+	0x65, 0x63, 0x6b, 0x56, 0x75, 0x6c, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x12,
+	0x2e, 0x53, 0x74, 0x61, 0x72, 0x74, 0x53, 0x63, 0x61, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x2c, 0x0a, 0x07, 0x47, 0x65, 0x74, 0x53, 0x63, 0x61, 0x6e, 0x12, 0x0f, 0x2e,
+	0x47, 0x65, 0x74, 0x53, 0x63, 0x61, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x10,
+	0x2e, 0x47, 0x65, 0x74, 0x53, 0x63, 0x61, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x32, 0x0a, 0x09, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x63, 0x61, 0x6e, 0x73, 0x12, 0x11, 0x2e,
+	0x4c, 0x69, 0x73, 0x74, 0x53, 0x63, 0x61, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x12, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x63, 0x61, 0x6e, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x32, 0x0a, 0x09, 0x44, 0x69, 0x66, 0x66, 0x53, 0x63, 0x61, 0x6e,
+	0x73, 0x12, 0x11, 0x2e, 0x44, 0x69, 0x66, 0x66, 0x53, 0x63, 0x61, 0x6e, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x44, 0x69, 0x66, 0x66, 0x53, 0x63, 0x61, 0x6e, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3e, 0x0a, 0x0d, 0x49, 0x6d, 0x70, 0x6f,
+	0x72, 0x74, 0x4e, 0x6d, 0x61, 0x70, 0x58, 0x4d, 0x4c, 0x12, 0x15, 0x2e, 0x49, 0x6d, 0x70, 0x6f,
+	0x72, 0x74, 0x4e, 0x6d, 0x61, 0x70, 0x58, 0x4d, 0x4c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x16, 0x2e, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x4e, 0x6d, 0x61, 0x70, 0x58, 0x4d, 0x4c,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x35, 0x0a, 0x0a, 0x43, 0x61, 0x6e, 0x63,
+	0x65, 0x6c, 0x53, 0x63, 0x61, 0x6e, 0x12, 0x12, 0x2e, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x53,
+	0x63, 0x61, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x43, 0x61, 0x6e,
+	0x63, 0x65, 0x6c, 0x53, 0x63, 0x61, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42,
+	0x4b, 0x5a, 0x49, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x4e, 0x69,
+	0x6b, 0x6f, 0x6c, 0x61, 0x42, 0x31, 0x33, 0x31, 0x2f, 0x6e, 0x6d, 0x61, 0x70, 0x2d, 0x76, 0x75,
+	0x6c, 0x6e, 0x65, 0x72, 0x73, 0x2d, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2f, 0x70, 0x6b,
+	0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x6e, 0x6d, 0x61, 0x70, 0x2d, 0x76, 0x75, 0x6c,
+	0x6e, 0x65, 0x72, 0x73, 0x2d, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x62, 0x06, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_pkg_proto_nmap_vulners_service_proto_rawDescOnce sync.Once
+	file_pkg_proto_nmap_vulners_service_proto_rawDescData = file_pkg_proto_nmap_vulners_service_proto_rawDesc
+)
+
+func file_pkg_proto_nmap_vulners_service_proto_rawDescGZIP() []byte {
+	file_pkg_proto_nmap_vulners_service_proto_rawDescOnce.Do(func() {
+		file_pkg_proto_nmap_vulners_service_proto_rawDescData = protoimpl.X.CompressGZIP(file_pkg_proto_nmap_vulners_service_proto_rawDescData)
+	})
+	return file_pkg_proto_nmap_vulners_service_proto_rawDescData
+}
+
+var file_pkg_proto_nmap_vulners_service_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_pkg_proto_nmap_vulners_service_proto_msgTypes = make([]protoimpl.MessageInfo, 25)
+var file_pkg_proto_nmap_vulners_service_proto_goTypes = []interface{}{
+	(VulnsSort)(0),                  // 0: VulnsSort
+	(ScanStatus)(0),                 // 1: ScanStatus
+	(*CheckVulnRequest)(nil),        // 2: CheckVulnRequest
+	(*CheckVulnResponse)(nil),       // 3: CheckVulnResponse
+	(*CheckVulnStreamResponse)(nil), // 4: CheckVulnStreamResponse
+	(*ResolvedTargets)(nil),         // 5: ResolvedTargets
+	(*ResolvedTarget)(nil),          // 6: ResolvedTarget
+	(*ScanSummary)(nil),             // 7: ScanSummary
+	(*StartScanResponse)(nil),       // 8: StartScanResponse
+	(*GetScanRequest)(nil),          // 9: GetScanRequest
+	(*GetScanResponse)(nil),         // 10: GetScanResponse
+	(*ListScansRequest)(nil),        // 11: ListScansRequest
+	(*ListScansResponse)(nil),       // 12: ListScansResponse
+	(*DiffScansRequest)(nil),        // 13: DiffScansRequest
+	(*DiffScansResponse)(nil),       // 14: DiffScansResponse
+	(*HostDiff)(nil),                // 15: HostDiff
+	(*ServiceDiff)(nil),             // 16: ServiceDiff
+	(*ImportNmapXMLRequest)(nil),    // 17: ImportNmapXMLRequest
+	(*ImportNmapXMLResponse)(nil),   // 18: ImportNmapXMLResponse
+	(*CancelScanRequest)(nil),       // 19: CancelScanRequest
+	(*CancelScanResponse)(nil),      // 20: CancelScanResponse
+	(*Scan)(nil),                    // 21: Scan
+	(*TargetsResult)(nil),           // 22: TargetsResult
+	(*OSGuess)(nil),                 // 23: OSGuess
+	(*ParseWarning)(nil),            // 24: ParseWarning
+	(*Service)(nil),                 // 25: Service
+	(*Vulnerability)(nil),           // 26: Vulnerability
+	(*durationpb.Duration)(nil),     // 27: google.protobuf.Duration
+	(*timestamppb.Timestamp)(nil),   // 28: google.protobuf.Timestamp
+}
+var file_pkg_proto_nmap_vulners_service_proto_depIdxs = []int32{
+	0,  // 0: CheckVulnRequest.sort:type_name -> VulnsSort
+	22, // 1: CheckVulnResponse.results:type_name -> TargetsResult
+	6,  // 2: CheckVulnResponse.targets:type_name -> ResolvedTarget
+	22, // 3: CheckVulnStreamResponse.result:type_name -> TargetsResult
+	7,  // 4: CheckVulnStreamResponse.summary:type_name -> ScanSummary
+	5,  // 5: CheckVulnStreamResponse.targets:type_name -> ResolvedTargets
+	6,  // 6: ResolvedTargets.targets:type_name -> ResolvedTarget
+	27, // 7: ScanSummary.elapsed:type_name -> google.protobuf.Duration
+	6,  // 8: StartScanResponse.targets:type_name -> ResolvedTarget
+	21, // 9: GetScanResponse.scan:type_name -> Scan
+	28, // 10: ListScansRequest.since:type_name -> google.protobuf.Timestamp
+	21, // 11: ListScansResponse.scans:type_name -> Scan
+	15, // 12: DiffScansResponse.hosts:type_name -> HostDiff
+	25, // 13: HostDiff.opened_ports:type_name -> Service
+	25, // 14: HostDiff.closed_ports:type_name -> Service
+	16, // 15: HostDiff.changed_services:type_name -> ServiceDiff
+	26, // 16: ServiceDiff.new_vulns:type_name -> Vulnerability
+	26, // 17: ServiceDiff.resolved_vulns:type_name -> Vulnerability
+	21, // 18: ImportNmapXMLResponse.scan:type_name -> Scan
+	21, // 19: CancelScanResponse.scan:type_name -> Scan
+	1,  // 20: Scan.status:type_name -> ScanStatus
+	28, // 21: Scan.created_at:type_name -> google.protobuf.Timestamp
+	28, // 22: Scan.started_at:type_name -> google.protobuf.Timestamp
+	28, // 23: Scan.finished_at:type_name -> google.protobuf.Timestamp
+	22, // 24: Scan.results:type_name -> TargetsResult
+	7,  // 25: Scan.summary:type_name -> ScanSummary
+	25, // 26: TargetsResult.services:type_name -> Service
+	24, // 27: TargetsResult.parse_warnings:type_name -> ParseWarning
+	23, // 28: TargetsResult.os:type_name -> OSGuess
+	26, // 29: Service.vulns:type_name -> Vulnerability
+	28, // 30: Vulnerability.kev_date_added:type_name -> google.protobuf.Timestamp
+	2,  // 31: NetVulnService.CheckVuln:input_type -> CheckVulnRequest
+	2,  // 32: NetVulnService.CheckVulnStream:input_type -> CheckVulnRequest
+	2,  // 33: NetVulnService.StartScan:input_type -> CheckVulnRequest
+	9,  // 34: NetVulnService.GetScan:input_type -> GetScanRequest
+	11, // 35: NetVulnService.ListScans:input_type -> ListScansRequest
+	13, // 36: NetVulnService.DiffScans:input_type -> DiffScansRequest
+	17, // 37: NetVulnService.ImportNmapXML:input_type -> ImportNmapXMLRequest
+	19, // 38: NetVulnService.CancelScan:input_type -> CancelScanRequest
+	3,  // 39: NetVulnService.CheckVuln:output_type -> CheckVulnResponse
+	4,  // 40: NetVulnService.CheckVulnStream:output_type -> CheckVulnStreamResponse
+	8,  // 41: NetVulnService.StartScan:output_type -> StartScanResponse
+	10, // 42: NetVulnService.GetScan:output_type -> GetScanResponse
+	12, // 43: NetVulnService.ListScans:output_type -> ListScansResponse
+	14, // 44: NetVulnService.DiffScans:output_type -> DiffScansResponse
+	18, // 45: NetVulnService.ImportNmapXML:output_type -> ImportNmapXMLResponse
+	20, // 46: NetVulnService.CancelScan:output_type -> CancelScanResponse
+	39, // [39:47] is the sub-list for method output_type
+	31, // [31:39] is the sub-list for method input_type
+	31, // [31:31] is the sub-list for extension type_name
+	31, // [31:31] is the sub-list for extension extendee
+	0,  // [0:31] is the sub-list for field type_name
+}
+
+func init() { file_pkg_proto_nmap_vulners_service_proto_init() }
+func file_pkg_proto_nmap_vulners_service_proto_init() {
+	if File_pkg_proto_nmap_vulners_service_proto != nil {
+		return
+	}
 	if !protoimpl.UnsafeEnabled {
 		file_pkg_proto_nmap_vulners_service_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*CheckVulnRequest); i {
@@ -411,7 +2437,7 @@ func file_pkg_proto_nmap_vulners_service_proto_init() {
 			}
 		}
 		file_pkg_proto_nmap_vulners_service_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*TargetsResult); i {
+			switch v := v.(*CheckVulnStreamResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -423,7 +2449,7 @@ func file_pkg_proto_nmap_vulners_service_proto_init() {
 			}
 		}
 		file_pkg_proto_nmap_vulners_service_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Service); i {
+			switch v := v.(*ResolvedTargets); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -435,6 +2461,246 @@ func file_pkg_proto_nmap_vulners_service_proto_init() {
 			}
 		}
 		file_pkg_proto_nmap_vulners_service_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResolvedTarget); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_nmap_vulners_service_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ScanSummary); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_nmap_vulners_service_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StartScanResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_nmap_vulners_service_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetScanRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_nmap_vulners_service_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetScanResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_nmap_vulners_service_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListScansRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_nmap_vulners_service_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListScansResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_nmap_vulners_service_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DiffScansRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_nmap_vulners_service_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DiffScansResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_nmap_vulners_service_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*HostDiff); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_nmap_vulners_service_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ServiceDiff); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_nmap_vulners_service_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ImportNmapXMLRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_nmap_vulners_service_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ImportNmapXMLResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_nmap_vulners_service_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CancelScanRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_nmap_vulners_service_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CancelScanResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_nmap_vulners_service_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Scan); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_nmap_vulners_service_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TargetsResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_nmap_vulners_service_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*OSGuess); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_nmap_vulners_service_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ParseWarning); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_nmap_vulners_service_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Service); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_nmap_vulners_service_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*Vulnerability); i {
 			case 0:
 				return &v.state
@@ -447,18 +2713,24 @@ func file_pkg_proto_nmap_vulners_service_proto_init() {
 			}
 		}
 	}
+	file_pkg_proto_nmap_vulners_service_proto_msgTypes[2].OneofWrappers = []interface{}{
+		(*CheckVulnStreamResponse_Result)(nil),
+		(*CheckVulnStreamResponse_Summary)(nil),
+		(*CheckVulnStreamResponse_Targets)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_pkg_proto_nmap_vulners_service_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   5,
+			NumEnums:      2,
+			NumMessages:   25,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_pkg_proto_nmap_vulners_service_proto_goTypes,
 		DependencyIndexes: file_pkg_proto_nmap_vulners_service_proto_depIdxs,
+		EnumInfos:         file_pkg_proto_nmap_vulners_service_proto_enumTypes,
 		MessageInfos:      file_pkg_proto_nmap_vulners_service_proto_msgTypes,
 	}.Build()
 	File_pkg_proto_nmap_vulners_service_proto = out.File
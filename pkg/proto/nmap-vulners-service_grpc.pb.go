@@ -19,7 +19,14 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	NetVulnService_CheckVuln_FullMethodName = "/NetVulnService/CheckVuln"
+	NetVulnService_CheckVuln_FullMethodName       = "/NetVulnService/CheckVuln"
+	NetVulnService_CheckVulnStream_FullMethodName = "/NetVulnService/CheckVulnStream"
+	NetVulnService_StartScan_FullMethodName       = "/NetVulnService/StartScan"
+	NetVulnService_GetScan_FullMethodName         = "/NetVulnService/GetScan"
+	NetVulnService_ListScans_FullMethodName       = "/NetVulnService/ListScans"
+	NetVulnService_DiffScans_FullMethodName       = "/NetVulnService/DiffScans"
+	NetVulnService_ImportNmapXML_FullMethodName   = "/NetVulnService/ImportNmapXML"
+	NetVulnService_CancelScan_FullMethodName      = "/NetVulnService/CancelScan"
 )
 
 // NetVulnServiceClient is the client API for NetVulnService service.
@@ -27,6 +34,13 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type NetVulnServiceClient interface {
 	CheckVuln(ctx context.Context, in *CheckVulnRequest, opts ...grpc.CallOption) (*CheckVulnResponse, error)
+	CheckVulnStream(ctx context.Context, in *CheckVulnRequest, opts ...grpc.CallOption) (NetVulnService_CheckVulnStreamClient, error)
+	StartScan(ctx context.Context, in *CheckVulnRequest, opts ...grpc.CallOption) (*StartScanResponse, error)
+	GetScan(ctx context.Context, in *GetScanRequest, opts ...grpc.CallOption) (*GetScanResponse, error)
+	ListScans(ctx context.Context, in *ListScansRequest, opts ...grpc.CallOption) (*ListScansResponse, error)
+	DiffScans(ctx context.Context, in *DiffScansRequest, opts ...grpc.CallOption) (*DiffScansResponse, error)
+	ImportNmapXML(ctx context.Context, in *ImportNmapXMLRequest, opts ...grpc.CallOption) (*ImportNmapXMLResponse, error)
+	CancelScan(ctx context.Context, in *CancelScanRequest, opts ...grpc.CallOption) (*CancelScanResponse, error)
 }
 
 type netVulnServiceClient struct {
@@ -46,11 +60,104 @@ func (c *netVulnServiceClient) CheckVuln(ctx context.Context, in *CheckVulnReque
 	return out, nil
 }
 
+func (c *netVulnServiceClient) CheckVulnStream(ctx context.Context, in *CheckVulnRequest, opts ...grpc.CallOption) (NetVulnService_CheckVulnStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &NetVulnService_ServiceDesc.Streams[0], NetVulnService_CheckVulnStream_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &netVulnServiceCheckVulnStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type NetVulnService_CheckVulnStreamClient interface {
+	Recv() (*CheckVulnStreamResponse, error)
+	grpc.ClientStream
+}
+
+type netVulnServiceCheckVulnStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *netVulnServiceCheckVulnStreamClient) Recv() (*CheckVulnStreamResponse, error) {
+	m := new(CheckVulnStreamResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *netVulnServiceClient) StartScan(ctx context.Context, in *CheckVulnRequest, opts ...grpc.CallOption) (*StartScanResponse, error) {
+	out := new(StartScanResponse)
+	err := c.cc.Invoke(ctx, NetVulnService_StartScan_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *netVulnServiceClient) GetScan(ctx context.Context, in *GetScanRequest, opts ...grpc.CallOption) (*GetScanResponse, error) {
+	out := new(GetScanResponse)
+	err := c.cc.Invoke(ctx, NetVulnService_GetScan_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *netVulnServiceClient) ListScans(ctx context.Context, in *ListScansRequest, opts ...grpc.CallOption) (*ListScansResponse, error) {
+	out := new(ListScansResponse)
+	err := c.cc.Invoke(ctx, NetVulnService_ListScans_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *netVulnServiceClient) DiffScans(ctx context.Context, in *DiffScansRequest, opts ...grpc.CallOption) (*DiffScansResponse, error) {
+	out := new(DiffScansResponse)
+	err := c.cc.Invoke(ctx, NetVulnService_DiffScans_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *netVulnServiceClient) ImportNmapXML(ctx context.Context, in *ImportNmapXMLRequest, opts ...grpc.CallOption) (*ImportNmapXMLResponse, error) {
+	out := new(ImportNmapXMLResponse)
+	err := c.cc.Invoke(ctx, NetVulnService_ImportNmapXML_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *netVulnServiceClient) CancelScan(ctx context.Context, in *CancelScanRequest, opts ...grpc.CallOption) (*CancelScanResponse, error) {
+	out := new(CancelScanResponse)
+	err := c.cc.Invoke(ctx, NetVulnService_CancelScan_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // NetVulnServiceServer is the server API for NetVulnService service.
 // All implementations must embed UnimplementedNetVulnServiceServer
 // for forward compatibility
 type NetVulnServiceServer interface {
 	CheckVuln(context.Context, *CheckVulnRequest) (*CheckVulnResponse, error)
+	CheckVulnStream(*CheckVulnRequest, NetVulnService_CheckVulnStreamServer) error
+	StartScan(context.Context, *CheckVulnRequest) (*StartScanResponse, error)
+	GetScan(context.Context, *GetScanRequest) (*GetScanResponse, error)
+	ListScans(context.Context, *ListScansRequest) (*ListScansResponse, error)
+	DiffScans(context.Context, *DiffScansRequest) (*DiffScansResponse, error)
+	ImportNmapXML(context.Context, *ImportNmapXMLRequest) (*ImportNmapXMLResponse, error)
+	CancelScan(context.Context, *CancelScanRequest) (*CancelScanResponse, error)
 	mustEmbedUnimplementedNetVulnServiceServer()
 }
 
@@ -61,6 +168,27 @@ type UnimplementedNetVulnServiceServer struct {
 func (UnimplementedNetVulnServiceServer) CheckVuln(context.Context, *CheckVulnRequest) (*CheckVulnResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CheckVuln not implemented")
 }
+func (UnimplementedNetVulnServiceServer) CheckVulnStream(*CheckVulnRequest, NetVulnService_CheckVulnStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method CheckVulnStream not implemented")
+}
+func (UnimplementedNetVulnServiceServer) StartScan(context.Context, *CheckVulnRequest) (*StartScanResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartScan not implemented")
+}
+func (UnimplementedNetVulnServiceServer) GetScan(context.Context, *GetScanRequest) (*GetScanResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetScan not implemented")
+}
+func (UnimplementedNetVulnServiceServer) ListScans(context.Context, *ListScansRequest) (*ListScansResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListScans not implemented")
+}
+func (UnimplementedNetVulnServiceServer) DiffScans(context.Context, *DiffScansRequest) (*DiffScansResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DiffScans not implemented")
+}
+func (UnimplementedNetVulnServiceServer) ImportNmapXML(context.Context, *ImportNmapXMLRequest) (*ImportNmapXMLResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportNmapXML not implemented")
+}
+func (UnimplementedNetVulnServiceServer) CancelScan(context.Context, *CancelScanRequest) (*CancelScanResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelScan not implemented")
+}
 func (UnimplementedNetVulnServiceServer) mustEmbedUnimplementedNetVulnServiceServer() {}
 
 // UnsafeNetVulnServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -92,6 +220,135 @@ func _NetVulnService_CheckVuln_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _NetVulnService_CheckVulnStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(CheckVulnRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(NetVulnServiceServer).CheckVulnStream(m, &netVulnServiceCheckVulnStreamServer{stream})
+}
+
+type NetVulnService_CheckVulnStreamServer interface {
+	Send(*CheckVulnStreamResponse) error
+	grpc.ServerStream
+}
+
+type netVulnServiceCheckVulnStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *netVulnServiceCheckVulnStreamServer) Send(m *CheckVulnStreamResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _NetVulnService_StartScan_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckVulnRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NetVulnServiceServer).StartScan(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NetVulnService_StartScan_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NetVulnServiceServer).StartScan(ctx, req.(*CheckVulnRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NetVulnService_GetScan_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetScanRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NetVulnServiceServer).GetScan(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NetVulnService_GetScan_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NetVulnServiceServer).GetScan(ctx, req.(*GetScanRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NetVulnService_ListScans_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListScansRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NetVulnServiceServer).ListScans(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NetVulnService_ListScans_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NetVulnServiceServer).ListScans(ctx, req.(*ListScansRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NetVulnService_DiffScans_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DiffScansRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NetVulnServiceServer).DiffScans(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NetVulnService_DiffScans_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NetVulnServiceServer).DiffScans(ctx, req.(*DiffScansRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NetVulnService_ImportNmapXML_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportNmapXMLRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NetVulnServiceServer).ImportNmapXML(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NetVulnService_ImportNmapXML_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NetVulnServiceServer).ImportNmapXML(ctx, req.(*ImportNmapXMLRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NetVulnService_CancelScan_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelScanRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NetVulnServiceServer).CancelScan(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NetVulnService_CancelScan_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NetVulnServiceServer).CancelScan(ctx, req.(*CancelScanRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // NetVulnService_ServiceDesc is the grpc.ServiceDesc for NetVulnService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -103,7 +360,37 @@ var NetVulnService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CheckVuln",
 			Handler:    _NetVulnService_CheckVuln_Handler,
 		},
+		{
+			MethodName: "StartScan",
+			Handler:    _NetVulnService_StartScan_Handler,
+		},
+		{
+			MethodName: "GetScan",
+			Handler:    _NetVulnService_GetScan_Handler,
+		},
+		{
+			MethodName: "ListScans",
+			Handler:    _NetVulnService_ListScans_Handler,
+		},
+		{
+			MethodName: "DiffScans",
+			Handler:    _NetVulnService_DiffScans_Handler,
+		},
+		{
+			MethodName: "ImportNmapXML",
+			Handler:    _NetVulnService_ImportNmapXML_Handler,
+		},
+		{
+			MethodName: "CancelScan",
+			Handler:    _NetVulnService_CancelScan_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "CheckVulnStream",
+			Handler:       _NetVulnService_CheckVulnStream_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "pkg/proto/nmap-vulners-service.proto",
 }
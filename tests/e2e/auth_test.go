@@ -0,0 +1,330 @@
+package tests
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"math/big"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/NikolaB131/nmap-vulners-service/internal/auth"
+	"github.com/NikolaB131/nmap-vulners-service/internal/service"
+	"github.com/NikolaB131/nmap-vulners-service/internal/target"
+	nmap_vulners_service "github.com/NikolaB131/nmap-vulners-service/pkg/proto"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	jwtIssuer   = "https://idp.example.com"
+	jwtAudience = "nmap-vulners-service"
+
+	adminKey   = "admin-key-0123456789"
+	viewerKey  = "viewer-key-0123456789"
+	scannerKey = "scanner-key-0123456789"
+	auditorKey = "auditor-key-0123456789"
+)
+
+// AuthReplaySuite checks authentication by API keys and JWTs and authorization by roles, nmap output is replayed
+type AuthReplaySuite struct {
+	suite.Suite
+	jwksPath    string
+	jwtVerifier *auth.JWTVerifier
+	ecKey       *ecdsa.PrivateKey
+	rsaKey      *rsa.PrivateKey
+	edKey       ed25519.PrivateKey
+
+	harness *VulnersControllerSuite
+	Client  nmap_vulners_service.NetVulnServiceClient
+}
+
+func TestAuthReplaySuite(t *testing.T) {
+	suite.Run(t, new(AuthReplaySuite))
+}
+
+func (s *AuthReplaySuite) SetupSuite() {
+	var err error
+	s.ecKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.Require().NoError(err)
+	s.rsaKey, err = rsa.GenerateKey(rand.Reader, 2048)
+	s.Require().NoError(err)
+	_, s.edKey, err = ed25519.GenerateKey(rand.Reader)
+	s.Require().NoError(err)
+
+	s.jwksPath = filepath.Join(s.T().TempDir(), "jwks.json")
+	s.writeJWKS(map[string]crypto.PublicKey{"ec": &s.ecKey.PublicKey, "rsa": &s.rsaKey.PublicKey, "ed": s.edKey.Public()})
+	s.jwtVerifier, err = auth.NewJWTVerifier(slog.Default(), s.jwksPath, jwtIssuer, jwtAudience, "roles")
+	s.Require().NoError(err)
+
+	roles := []auth.Role{
+		{Name: "admin", Methods: []string{auth.AnyMethod}},
+		{Name: "viewer", Methods: []string{"GetScan", "ListScans", "DiffScans"}},
+		{Name: "scanner", Methods: []string{"CheckVuln", "CheckVulnStream", "StartScan", "GetScan"}, Scope: &target.Scope{Allow: []netip.Prefix{netip.MustParsePrefix("127.0.0.0/8")}}},
+		{Name: "auditor", Methods: []string{"GetScan", "ListScans", "DiffScans", "CancelScan"}, Scope: &target.Scope{Allow: []netip.Prefix{netip.MustParsePrefix("127.0.0.0/8")}}},
+	}
+	keys := []auth.APIKey{
+		{Name: "admin", Key: adminKey, Roles: []string{"admin"}},
+		{Name: "dashboard", Key: viewerKey, Roles: []string{"viewer"}},
+		{Name: "ci", Key: scannerKey, Roles: []string{"scanner"}},
+		{Name: "audit", Key: auditorKey, Roles: []string{"auditor"}},
+	}
+	authenticator, err := auth.NewAuthenticator(slog.Default(), &nmap_vulners_service.NetVulnService_ServiceDesc, roles, keys, s.jwtVerifier)
+	s.Require().NoError(err)
+
+	s.harness = &VulnersControllerSuite{
+		newScanner: func(string) service.Scanner {
+			return service.NewReplayScanner("./testdata/replay", 0)
+		},
+		authenticator: authenticator,
+	}
+	startHarness(s.T(), s.harness)
+	s.Client = s.harness.Client
+}
+
+func (s *AuthReplaySuite) TearDownSuite() {
+	s.harness.TearDownSuite()
+}
+
+func (s *AuthReplaySuite) writeJWKS(keys map[string]crypto.PublicKey) {
+	encode := func(data []byte) string { return base64.RawURLEncoding.EncodeToString(data) }
+
+	var set struct {
+		Keys []map[string]string `json:"keys"`
+	}
+	for kid, key := range keys {
+		switch key := key.(type) {
+		case *ecdsa.PublicKey:
+			set.Keys = append(set.Keys, map[string]string{"kty": "EC", "kid": kid, "use": "sig", "crv": "P-256", "x": encode(key.X.FillBytes(make([]byte, 32))), "y": encode(key.Y.FillBytes(make([]byte, 32)))})
+		case *rsa.PublicKey:
+			set.Keys = append(set.Keys, map[string]string{"kty": "RSA", "kid": kid, "alg": "RS256", "n": encode(key.N.Bytes()), "e": encode(big.NewInt(int64(key.E)).Bytes())})
+		case ed25519.PublicKey:
+			set.Keys = append(set.Keys, map[string]string{"kty": "OKP", "kid": kid, "crv": "Ed25519", "x": encode(key)})
+		}
+	}
+	// Encryption keys are skipped
+	set.Keys = append(set.Keys, map[string]string{"kty": "RSA", "kid": "enc", "use": "enc", "n": "AQAB", "e": "AQAB"})
+
+	data, err := json.Marshal(set)
+	s.Require().NoError(err)
+	s.Require().NoError(os.WriteFile(s.jwksPath, data, 0o600))
+}
+
+// token signs claims merged into valid default ones, nil values remove default claims
+func (s *AuthReplaySuite) token(method jwt.SigningMethod, key crypto.PrivateKey, kid string, claims jwt.MapClaims) string {
+	now := time.Now()
+	all := jwt.MapClaims{"iss": jwtIssuer, "aud": jwtAudience, "sub": "alice", "iat": now.Unix(), "exp": now.Add(time.Hour).Unix()}
+	for name, value := range claims {
+		if value == nil {
+			delete(all, name)
+			continue
+		}
+		all[name] = value
+	}
+	token := jwt.NewWithClaims(method, all)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	s.Require().NoError(err)
+	return signed
+}
+
+func withToken(token string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+func (s *AuthReplaySuite) checkLocalhost(ctx context.Context) error {
+	_, err := s.Client.CheckVuln(ctx, &nmap_vulners_service.CheckVulnRequest{Targets: []string{"localhost"}, TcpPorts: []int32{11001}})
+	return err
+}
+
+func (s *AuthReplaySuite) TestMissingToken() {
+	err := s.checkLocalhost(context.Background())
+	s.Equal(codes.Unauthenticated, status.Code(err))
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Basic "+adminKey)
+	err = s.checkLocalhost(ctx)
+	s.Equal(codes.Unauthenticated, status.Code(err))
+
+	stream, err := s.Client.CheckVulnStream(context.Background(), &nmap_vulners_service.CheckVulnRequest{Targets: []string{"localhost"}})
+	s.Require().NoError(err)
+	_, err = stream.Recv()
+	s.Equal(codes.Unauthenticated, status.Code(err))
+}
+
+func (s *AuthReplaySuite) TestAPIKeys() {
+	err := s.checkLocalhost(withToken("wrong-key-0123456789"))
+	s.Equal(codes.Unauthenticated, status.Code(err))
+
+	_, err = s.Client.ListScans(withToken(viewerKey), &nmap_vulners_service.ListScansRequest{})
+	s.NoError(err)
+	err = s.checkLocalhost(withToken(viewerKey))
+	s.Equal(codes.PermissionDenied, status.Code(err), "viewer cannot scan")
+
+	err = s.checkLocalhost(withToken(scannerKey))
+	s.NoError(err)
+	_, err = s.Client.ListScans(withToken(scannerKey), &nmap_vulners_service.ListScansRequest{})
+	s.Equal(codes.PermissionDenied, status.Code(err), "scanner cannot list scans")
+
+	_, err = s.Client.ListScans(withToken(adminKey), &nmap_vulners_service.ListScansRequest{})
+	s.NoError(err)
+	err = s.checkLocalhost(metadata.AppendToOutgoingContext(context.Background(), "authorization", "bearer "+adminKey))
+	s.NoError(err, "scheme is case insensitive")
+}
+
+func (s *AuthReplaySuite) TestStream() {
+	stream, err := s.Client.CheckVulnStream(withToken(scannerKey), &nmap_vulners_service.CheckVulnRequest{Targets: []string{"localhost"}, TcpPorts: []int32{11001}})
+	s.Require().NoError(err)
+	messages := 0
+	for {
+		_, err = stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		s.Require().NoError(err)
+		messages++
+	}
+	s.Equal(3, messages, "targets, result and summary")
+
+	// The scope of a role is checked in streams too, the identity is passed through the stream context
+	stream, err = s.Client.CheckVulnStream(withToken(scannerKey), &nmap_vulners_service.CheckVulnRequest{Targets: []string{"10.0.0.1"}})
+	s.Require().NoError(err)
+	_, err = stream.Recv()
+	s.Equal(codes.PermissionDenied, status.Code(err))
+}
+
+func (s *AuthReplaySuite) TestRoleScope() {
+	_, err := s.Client.StartScan(withToken(scannerKey), &nmap_vulners_service.CheckVulnRequest{Targets: []string{"127.0.0.1", "10.0.0.0/30", "ya.ru"}})
+	s.Equal(codes.PermissionDenied, status.Code(err))
+	s.Contains(status.Convert(err).Message(), "10.0.0.0/30, ya.ru")
+
+	// Roles which do not allow the method do not widen the scope, viewer has no scope limits but cannot scan
+	token := s.token(jwt.SigningMethodES256, s.ecKey, "ec", jwt.MapClaims{"roles": []string{"viewer", "scanner"}})
+	_, err = s.Client.StartScan(withToken(token), &nmap_vulners_service.CheckVulnRequest{Targets: []string{"10.0.0.1"}})
+	s.Equal(codes.PermissionDenied, status.Code(err))
+	s.Contains(status.Convert(err).Message(), "out of allowed scope")
+}
+
+// startScan starts a scan as admin and waits for it to be done
+func (s *AuthReplaySuite) startScan(targets ...string) string {
+	response, err := s.Client.StartScan(withToken(adminKey), &nmap_vulners_service.CheckVulnRequest{Targets: targets, TcpPorts: []int32{11001}})
+	s.Require().NoError(err)
+	s.Eventually(func() bool {
+		scan, err := s.Client.GetScan(withToken(adminKey), &nmap_vulners_service.GetScanRequest{ScanId: response.GetScanId()})
+		s.Require().NoError(err)
+		return scan.GetScan().GetStatus() == nmap_vulners_service.ScanStatus_SCAN_STATUS_DONE
+	}, 10*time.Second, 10*time.Millisecond)
+	return response.GetScanId()
+}
+
+func (s *AuthReplaySuite) TestRoleScopeOfStoredScans() {
+	localID := s.startScan("localhost")
+	remoteID := s.startScan("ya.ru")
+	mixedID := s.startScan("127.0.0.2", "ya.ru")
+
+	_, err := s.Client.GetScan(withToken(auditorKey), &nmap_vulners_service.GetScanRequest{ScanId: localID})
+	s.NoError(err)
+	for _, id := range []string{remoteID, mixedID} {
+		_, err = s.Client.GetScan(withToken(auditorKey), &nmap_vulners_service.GetScanRequest{ScanId: id})
+		s.Equal(codes.PermissionDenied, status.Code(err), "a scan with any target out of the role scope")
+		_, err = s.Client.GetScan(withToken(scannerKey), &nmap_vulners_service.GetScanRequest{ScanId: id})
+		s.Equal(codes.PermissionDenied, status.Code(err))
+		_, err = s.Client.GetScan(withToken(viewerKey), &nmap_vulners_service.GetScanRequest{ScanId: id})
+		s.NoError(err, "roles without scope see every scan")
+	}
+
+	list, err := s.Client.ListScans(withToken(auditorKey), &nmap_vulners_service.ListScansRequest{Limit: 1})
+	s.Require().NoError(err)
+	s.Require().Len(list.GetScans(), 1, "the limit is applied after scans out of the scope are dropped")
+	s.Equal(localID, list.GetScans()[0].GetId())
+	list, err = s.Client.ListScans(withToken(auditorKey), &nmap_vulners_service.ListScansRequest{})
+	s.Require().NoError(err)
+	for _, scan := range list.GetScans() {
+		s.NotContains([]string{remoteID, mixedID}, scan.GetId())
+	}
+
+	_, err = s.Client.DiffScans(withToken(auditorKey), &nmap_vulners_service.DiffScansRequest{OldScanId: localID, NewScanId: mixedID})
+	s.Equal(codes.PermissionDenied, status.Code(err))
+	_, err = s.Client.DiffScans(withToken(auditorKey), &nmap_vulners_service.DiffScansRequest{OldScanId: localID, NewScanId: localID})
+	s.NoError(err)
+
+	_, err = s.Client.CancelScan(withToken(auditorKey), &nmap_vulners_service.CancelScanRequest{ScanId: remoteID})
+	s.Equal(codes.PermissionDenied, status.Code(err), "checked before the scan state")
+	_, err = s.Client.CancelScan(withToken(auditorKey), &nmap_vulners_service.CancelScanRequest{ScanId: localID})
+	s.Equal(codes.FailedPrecondition, status.Code(err), "already done")
+}
+
+func (s *AuthReplaySuite) TestJWT() {
+	tests := []struct {
+		name     string
+		token    string
+		expected codes.Code
+	}{
+		{"ES256", s.token(jwt.SigningMethodES256, s.ecKey, "ec", jwt.MapClaims{"roles": []string{"scanner"}}), codes.OK},
+		{"RS256 with space separated roles", s.token(jwt.SigningMethodRS256, s.rsaKey, "rsa", jwt.MapClaims{"roles": "viewer scanner"}), codes.OK},
+		{"EdDSA", s.token(jwt.SigningMethodEdDSA, s.edKey, "ed", jwt.MapClaims{"roles": []string{"admin"}}), codes.OK},
+		{"unknown roles only", s.token(jwt.SigningMethodES256, s.ecKey, "ec", jwt.MapClaims{"roles": []string{"root"}}), codes.PermissionDenied},
+		{"no roles", s.token(jwt.SigningMethodES256, s.ecKey, "ec", nil), codes.PermissionDenied},
+		{"expired", s.token(jwt.SigningMethodES256, s.ecKey, "ec", jwt.MapClaims{"roles": []string{"admin"}, "exp": time.Now().Add(-time.Hour).Unix()}), codes.Unauthenticated},
+		{"no exp", s.token(jwt.SigningMethodES256, s.ecKey, "ec", jwt.MapClaims{"roles": []string{"admin"}, "exp": nil}), codes.Unauthenticated},
+		{"no sub", s.token(jwt.SigningMethodES256, s.ecKey, "ec", jwt.MapClaims{"roles": []string{"admin"}, "sub": nil}), codes.Unauthenticated},
+		{"wrong issuer", s.token(jwt.SigningMethodES256, s.ecKey, "ec", jwt.MapClaims{"roles": []string{"admin"}, "iss": "https://evil.example.com"}), codes.Unauthenticated},
+		{"wrong audience", s.token(jwt.SigningMethodES256, s.ecKey, "ec", jwt.MapClaims{"roles": []string{"admin"}, "aud": "other-service"}), codes.Unauthenticated},
+		{"unknown kid", s.token(jwt.SigningMethodES256, s.ecKey, "other", jwt.MapClaims{"roles": []string{"admin"}}), codes.Unauthenticated},
+		{"no kid with several keys", s.token(jwt.SigningMethodES256, s.ecKey, "", jwt.MapClaims{"roles": []string{"admin"}}), codes.Unauthenticated},
+		{"algorithm of another key", s.token(jwt.SigningMethodPS256, s.rsaKey, "rsa", jwt.MapClaims{"roles": []string{"admin"}}), codes.Unauthenticated},
+		{"HMAC", s.token(jwt.SigningMethodHS256, []byte(adminKey), "ec", jwt.MapClaims{"roles": []string{"admin"}}), codes.Unauthenticated},
+	}
+
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.Require().NoError(err)
+	tests = append(tests, struct {
+		name     string
+		token    string
+		expected codes.Code
+	}{"signed by another key", s.token(jwt.SigningMethodES256, otherKey, "ec", jwt.MapClaims{"roles": []string{"admin"}}), codes.Unauthenticated})
+
+	for _, test := range tests {
+		err := s.checkLocalhost(withToken(test.token))
+		s.Equal(test.expected, status.Code(err), "%s: %v", test.name, err)
+	}
+}
+
+func (s *AuthReplaySuite) TestJWKSReload() {
+	rotatedKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.Require().NoError(err)
+	token := s.token(jwt.SigningMethodES256, rotatedKey, "rotated", jwt.MapClaims{"roles": []string{"scanner"}})
+	s.Equal(codes.Unauthenticated, status.Code(s.checkLocalhost(withToken(token))))
+
+	s.writeJWKS(map[string]crypto.PublicKey{"ec": &s.ecKey.PublicKey, "rsa": &s.rsaKey.PublicKey, "ed": s.edKey.Public(), "rotated": &rotatedKey.PublicKey})
+	s.Require().NoError(s.jwtVerifier.Reload())
+	s.NoError(s.checkLocalhost(withToken(token)))
+
+	// Broken JWKS is not loaded, the previous keys keep working
+	s.Require().NoError(os.WriteFile(s.jwksPath, []byte(`{"keys": [{"kty": "EC", "crv": "P-256", "x": "AA", "y": "AA"}]}`), 0o600))
+	s.Error(s.jwtVerifier.Reload())
+	s.NoError(s.checkLocalhost(withToken(token)))
+}
+
+func (s *AuthReplaySuite) TestAuthenticatorValidation() {
+	_, err := auth.NewAuthenticator(slog.Default(), &nmap_vulners_service.NetVulnService_ServiceDesc, []auth.Role{{Name: "typo", Methods: []string{"CheckVulns"}}}, nil, nil)
+	s.ErrorContains(err, `unknown method "CheckVulns"`)
+
+	_, err = auth.NewAuthenticator(slog.Default(), &nmap_vulners_service.NetVulnService_ServiceDesc, nil, []auth.APIKey{{Name: "ci", Key: scannerKey, Roles: []string{"scanner"}}}, nil)
+	s.ErrorContains(err, `unknown role "scanner"`)
+}
@@ -0,0 +1,17 @@
+//go:build live
+
+package tests
+
+import (
+	"testing"
+
+	"github.com/NikolaB131/nmap-vulners-service/internal/service"
+	"github.com/stretchr/testify/suite"
+)
+
+// Runs real nmap against the servers of make docker-mock-vuln-server, so it is built only with the live tag
+func TestVulnersControllerSuite(t *testing.T) {
+	suite.Run(t, &VulnersControllerSuite{newScanner: func(vulnersAPIURL string) service.Scanner {
+		return service.NewNmapScanner("../../scripts/vulners.nse", vulnersAPIURL, mockVulnersAPIKey)
+	}})
+}
@@ -0,0 +1,65 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/NikolaB131/nmap-vulners-service/internal/service"
+	nmap_vulners_service "github.com/NikolaB131/nmap-vulners-service/pkg/proto"
+	"github.com/stretchr/testify/suite"
+)
+
+// NVDSuite checks vulnerabilities matching against NVD feeds from ./testdata/nvd, nmap output is replayed
+type NVDSuite struct {
+	suite.Suite
+
+	harness *VulnersControllerSuite
+	Client  nmap_vulners_service.NetVulnServiceClient
+}
+
+func TestNVDReplaySuite(t *testing.T) {
+	suite.Run(t, new(NVDSuite))
+}
+
+func (s *NVDSuite) SetupSuite() {
+	s.harness = &VulnersControllerSuite{
+		newScanner: func(string) service.Scanner {
+			return service.NewReplayScanner("./testdata/replay", 0)
+		},
+		lookup: lookupNVD,
+	}
+	startHarness(s.T(), s.harness)
+	s.Client = s.harness.Client
+}
+
+func (s *NVDSuite) TearDownSuite() {
+	s.harness.TearDownSuite()
+}
+
+func (s *NVDSuite) TestCheckVuln() {
+	response, err := s.Client.CheckVuln(context.Background(), &nmap_vulners_service.CheckVulnRequest{
+		Targets:  []string{"localhost"},
+		TcpPorts: []int32{11001, 11002},
+	})
+	s.Require().NoError(err)
+
+	res := response.GetResults()[0]
+	s.Require().Len(res.Services, 2)
+
+	libssh := identifiers(res.Services[0].Vulns)
+	s.Equal([]string{"CVE-2019-14889", "CVE-2018-10933", "CVE-2020-1730"}, libssh, "sorted by cvss, versions out of range are skipped")
+	s.Equal(float32(9.8), res.Services[0].Vulns[0].CvssScore, "primary score of the newest cvss version is used")
+	s.Equal("https://nvd.nist.gov/vuln/detail/CVE-2018-10933", res.Services[0].Vulns[1].Url)
+
+	// igor_sysoev:nginx of nmap is checked as nginx:nginx too, the feed is gzipped
+	nginx := identifiers(res.Services[1].Vulns)
+	s.Equal([]string{"CVE-2017-7529", "CVE-2019-20372"}, nginx)
+}
+
+func identifiers(vulns []*nmap_vulners_service.Vulnerability) []string {
+	ids := make([]string, len(vulns))
+	for i, vuln := range vulns {
+		ids[i] = vuln.Identifier
+	}
+	return ids
+}
@@ -0,0 +1,329 @@
+package tests
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"log/slog"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/NikolaB131/nmap-vulners-service/internal/tlsconfig"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// tlsMode of the test harness server
+type tlsMode int
+
+const (
+	tlsDisabled tlsMode = iota
+	tlsServer           // the server presents a certificate
+	tlsMutual           // clients present certificates too
+)
+
+// testPKI is a certificate authority issuing certificates for a test server and its clients, files are written to dir
+type testPKI struct {
+	dir    string
+	caFile string
+	ca     *x509.Certificate
+	caKey  *ecdsa.PrivateKey
+}
+
+func newTestPKI(t *testing.T) *testPKI {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pki := &testPKI{dir: t.TempDir(), ca: ca, caKey: key}
+	pki.caFile = filepath.Join(pki.dir, "ca.pem")
+	writePEM(t, pki.caFile, "CERTIFICATE", der)
+	return pki
+}
+
+// issue writes a certificate for localhost signed by the CA into name.pem and its key into name-key.pem
+func (p *testPKI) issue(t *testing.T, name string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, p.ca, &key.PublicKey, p.caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certFile, keyFile := filepath.Join(p.dir, name+".pem"), filepath.Join(p.dir, name+"-key.pem")
+	writePEM(t, certFile, "CERTIFICATE", der)
+	writePEM(t, keyFile, "PRIVATE KEY", keyDER)
+	return certFile, keyFile
+}
+
+// clientConfig trusts the CA, with clientName set the client presents a certificate issued for that name
+func (p *testPKI) clientConfig(t *testing.T, clientName string) *tls.Config {
+	roots := x509.NewCertPool()
+	roots.AddCert(p.ca)
+	config := &tls.Config{RootCAs: roots, ServerName: "localhost"}
+	if clientName != "" {
+		certificate, err := tls.LoadX509KeyPair(p.issue(t, clientName))
+		if err != nil {
+			t.Fatal(err)
+		}
+		config.Certificates = []tls.Certificate{certificate}
+	}
+	return config
+}
+
+func writePEM(t *testing.T, path string, blockType string, der []byte) {
+	err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// newTestTransport returns server options and client credentials for mode, as the service builds them from grpc.tls config
+func newTestTransport(t *testing.T, mode tlsMode) ([]grpc.ServerOption, credentials.TransportCredentials) {
+	if mode == tlsDisabled {
+		return nil, insecure.NewCredentials()
+	}
+
+	pki := newTestPKI(t)
+	certFile, keyFile := pki.issue(t, "server")
+	files := tlsconfig.Files{CertFile: certFile, KeyFile: keyFile}
+	clientName := ""
+	if mode == tlsMutual {
+		files.ClientCAFile = pki.caFile
+		clientName = "client"
+	}
+	reloader, err := tlsconfig.NewReloader(slog.Default(), files, tls.VersionTLS12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return []grpc.ServerOption{grpc.Creds(credentials.NewTLS(reloader.ServerConfig()))}, credentials.NewTLS(pki.clientConfig(t, clientName))
+}
+
+// countingHandler counts logged errors
+type countingHandler struct {
+	slog.Handler
+	errors *atomic.Int32
+}
+
+func (h countingHandler) Handle(ctx context.Context, record slog.Record) error {
+	if record.Level >= slog.LevelError {
+		h.errors.Add(1)
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+type TLSReplaySuite struct {
+	suite.Suite
+	pki       *testPKI
+	certFile  string
+	keyFile   string
+	logErrors atomic.Int32 // errors logged by the reloader
+
+	cancelWatch    context.CancelFunc
+	serverListener *bufconn.Listener
+	server         *grpc.Server
+	serveErr       chan error // the result of server.Serve, read after the server is stopped
+}
+
+func TestTLSReplaySuite(t *testing.T) {
+	suite.Run(t, new(TLSReplaySuite))
+}
+
+func (s *TLSReplaySuite) SetupTest() {
+	s.pki = newTestPKI(s.T())
+	s.certFile, s.keyFile = s.pki.issue(s.T(), "server")
+	s.logErrors.Store(0)
+}
+
+func (s *TLSReplaySuite) TearDownTest() {
+	if s.server != nil {
+		s.server.Stop()
+		s.NoError(<-s.serveErr)
+		s.server = nil
+	}
+	if s.cancelWatch != nil {
+		s.cancelWatch()
+		s.cancelWatch = nil
+	}
+}
+
+// serve starts a server with the health service only, the transport is what is being tested
+func (s *TLSReplaySuite) serve(files tlsconfig.Files, minVersion uint16) {
+	logger := slog.New(countingHandler{Handler: slog.Default().Handler(), errors: &s.logErrors})
+	reloader, err := tlsconfig.NewReloader(logger, files, minVersion)
+	s.Require().NoError(err)
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancelWatch = cancel
+	go reloader.Watch(ctx, 20*time.Millisecond)
+
+	s.serverListener = bufconn.Listen(1024 * 1024)
+	s.server = grpc.NewServer(grpc.Creds(credentials.NewTLS(reloader.ServerConfig())))
+	healthpb.RegisterHealthServer(s.server, health.NewServer())
+	s.serveErr = make(chan error, 1)
+	go func() {
+		// asserted in TearDownTest, the test goroutine may be already gone when Serve returns
+		s.serveErr <- s.server.Serve(s.serverListener)
+	}()
+}
+
+// check makes a call on a new connection and returns the certificate the server presented
+func (s *TLSReplaySuite) check(creds credentials.TransportCredentials) (*x509.Certificate, error) {
+	conn, err := grpc.Dial(
+		"bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, str string) (net.Conn, error) {
+			return s.serverListener.Dial()
+		}),
+		grpc.WithTransportCredentials(creds),
+	)
+	s.Require().NoError(err)
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var callPeer peer.Peer
+	_, err = healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{}, grpc.Peer(&callPeer))
+	if err != nil {
+		return nil, err
+	}
+	tlsInfo, ok := callPeer.AuthInfo.(credentials.TLSInfo)
+	s.Require().True(ok)
+	return tlsInfo.State.PeerCertificates[0], nil
+}
+
+func (s *TLSReplaySuite) TestServerTLS() {
+	s.serve(tlsconfig.Files{CertFile: s.certFile, KeyFile: s.keyFile}, tls.VersionTLS12)
+
+	certificate, err := s.check(credentials.NewTLS(s.pki.clientConfig(s.T(), "")))
+	s.Require().NoError(err)
+	s.Equal("server", certificate.Subject.CommonName)
+
+	_, err = s.check(insecure.NewCredentials())
+	s.Error(err, "plaintext client must be rejected")
+
+	_, err = s.check(credentials.NewTLS(newTestPKI(s.T()).clientConfig(s.T(), "")))
+	s.Error(err, "client must not trust a server of another CA")
+}
+
+func (s *TLSReplaySuite) TestMutualTLS() {
+	s.serve(tlsconfig.Files{CertFile: s.certFile, KeyFile: s.keyFile, ClientCAFile: s.pki.caFile}, tls.VersionTLS12)
+
+	_, err := s.check(credentials.NewTLS(s.pki.clientConfig(s.T(), "client")))
+	s.NoError(err)
+
+	_, err = s.check(credentials.NewTLS(s.pki.clientConfig(s.T(), "")))
+	s.Error(err, "client without a certificate must be rejected")
+
+	otherConfig := newTestPKI(s.T()).clientConfig(s.T(), "other-client")
+	otherConfig.RootCAs = s.pki.clientConfig(s.T(), "").RootCAs
+	_, err = s.check(credentials.NewTLS(otherConfig))
+	s.Error(err, "client certificate of another CA must be rejected")
+}
+
+func (s *TLSReplaySuite) TestMinVersion() {
+	s.serve(tlsconfig.Files{CertFile: s.certFile, KeyFile: s.keyFile}, tls.VersionTLS13)
+
+	config := s.pki.clientConfig(s.T(), "")
+	config.MaxVersion = tls.VersionTLS12
+	_, err := s.check(credentials.NewTLS(config))
+	s.Error(err)
+
+	config.MaxVersion = tls.VersionTLS13
+	_, err = s.check(credentials.NewTLS(config))
+	s.NoError(err)
+}
+
+func (s *TLSReplaySuite) TestReload() {
+	s.serve(tlsconfig.Files{CertFile: s.certFile, KeyFile: s.keyFile}, tls.VersionTLS12)
+	creds := credentials.NewTLS(s.pki.clientConfig(s.T(), ""))
+
+	// The new pair replaces the old files, modification time is moved so the change is seen on coarse filesystems
+	newCertFile, newKeyFile := s.pki.issue(s.T(), "renewed-server")
+	s.Require().NoError(os.Rename(newKeyFile, s.keyFile))
+	s.Require().NoError(os.Rename(newCertFile, s.certFile))
+	s.Require().NoError(os.Chtimes(s.certFile, time.Now(), time.Now().Add(time.Minute)))
+	s.Eventually(func() bool {
+		certificate, err := s.check(creds)
+		return err == nil && certificate.Subject.CommonName == "renewed-server"
+	}, 5*time.Second, 50*time.Millisecond)
+
+	// A broken certificate is not loaded, the previous one keeps being served
+	s.Require().NoError(os.WriteFile(s.certFile, []byte("not a certificate"), 0o600))
+	s.Require().NoError(os.Chtimes(s.certFile, time.Now(), time.Now().Add(2*time.Minute)))
+	time.Sleep(200 * time.Millisecond)
+	certificate, err := s.check(creds)
+	s.Require().NoError(err)
+	s.Equal("renewed-server", certificate.Subject.CommonName)
+	s.Equal(int32(1), s.logErrors.Load(), "the broken file is reported once, not every tick")
+
+	// Fixed files are loaded after the failed attempt
+	fixedCertFile, fixedKeyFile := s.pki.issue(s.T(), "fixed-server")
+	s.Require().NoError(os.Rename(fixedKeyFile, s.keyFile))
+	s.Require().NoError(os.Rename(fixedCertFile, s.certFile))
+	s.Require().NoError(os.Chtimes(s.certFile, time.Now(), time.Now().Add(3*time.Minute)))
+	s.Eventually(func() bool {
+		certificate, err := s.check(creds)
+		return err == nil && certificate.Subject.CommonName == "fixed-server"
+	}, 5*time.Second, 50*time.Millisecond)
+}
+
+func (s *TLSReplaySuite) TestParseVersion() {
+	version, err := tlsconfig.ParseVersion("1.3")
+	s.NoError(err)
+	s.Equal(uint16(tls.VersionTLS13), version)
+
+	_, err = tlsconfig.ParseVersion("1.0")
+	s.Error(err)
+}
@@ -2,44 +2,141 @@ package tests
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
+	"math"
 	"net"
+	"net/netip"
+	"os"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/NikolaB131/nmap-vulners-service/internal/auth"
 	grpccontroller "github.com/NikolaB131/nmap-vulners-service/internal/controller/grpc"
+	"github.com/NikolaB131/nmap-vulners-service/internal/enrich"
+	"github.com/NikolaB131/nmap-vulners-service/internal/lookup"
+	"github.com/NikolaB131/nmap-vulners-service/internal/ports"
+	"github.com/NikolaB131/nmap-vulners-service/internal/repository/memory"
 	"github.com/NikolaB131/nmap-vulners-service/internal/service"
+	"github.com/NikolaB131/nmap-vulners-service/internal/target"
+	"github.com/NikolaB131/nmap-vulners-service/internal/vulnersmock"
 	nmap_vulners_service "github.com/NikolaB131/nmap-vulners-service/pkg/proto"
+	"github.com/Ullaakut/nmap/v3"
 	"github.com/stretchr/testify/suite"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/status"
 	"google.golang.org/grpc/test/bufconn"
 )
 
-const containsVulnMsg = "Result does not conatain vulnerability"
+const (
+	containsVulnMsg   = "Result does not conatain vulnerability"
+	mockVulnersAPIKey = "test-api-key"
+	maxHosts          = 256
+)
 
 var (
 	scanResultLocalhostPort11001 = []*nmap_vulners_service.Vulnerability{
 		{
 			Identifier: "CVE-2018-10933",
 			CvssScore:  6.4,
+			Type:       "cve",
+			Url:        "https://vulners.com/cve/CVE-2018-10933",
+			Cpe:        "cpe:/a:libssh:libssh:0.8.1",
 		},
 		{
 			Identifier: "CVE-2019-14889",
 			CvssScore:  9.3,
+			Type:       "cve",
+			Url:        "https://vulners.com/cve/CVE-2019-14889",
+			Cpe:        "cpe:/a:libssh:libssh:0.8.1",
 		},
 		{
 			Identifier: "CVE-2020-1730",
 			CvssScore:  5,
+			Type:       "cve",
+			Url:        "https://vulners.com/cve/CVE-2020-1730",
+			Cpe:        "cpe:/a:libssh:libssh:0.8.1",
 		},
 	}
 )
 
+// fixturesResolver resolves hostnames of replay fixtures without DNS, so scope checks do not depend on the network
+type fixturesResolver struct{}
+
+func (fixturesResolver) LookupNetIP(ctx context.Context, network string, host string) ([]netip.Addr, error) {
+	addresses := map[string]string{"localhost": "127.0.0.1", "nikolab131.xyz": "178.140.10.168", "ya.ru": "5.255.255.242"}
+	address, ok := addresses[host]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	return []netip.Addr{netip.MustParseAddr(address)}, nil
+}
+
+// rebindingHost resolves to an allowed address once and to a denied one afterwards, as a DNS server of an attacker
+// would answer to pass the scope check and make nmap scan something else
+const rebindingHost = "rebind.example.com"
+
+type rebindingResolver struct {
+	fixturesResolver
+	lookups atomic.Int32
+}
+
+func (r *rebindingResolver) LookupNetIP(ctx context.Context, network string, host string) ([]netip.Addr, error) {
+	if host != rebindingHost {
+		return r.fixturesResolver.LookupNetIP(ctx, network, host)
+	}
+	if r.lookups.Add(1) == 1 {
+		return []netip.Addr{netip.MustParseAddr("127.0.0.2")}, nil
+	}
+	return []netip.Addr{netip.MustParseAddr("192.0.2.1")}, nil
+}
+
+// recordingScanner remembers options every target was scanned with
+type recordingScanner struct {
+	service.Scanner
+
+	mu      sync.Mutex
+	options map[string]service.ScanOptions
+}
+
+func (s *recordingScanner) Scan(ctx context.Context, target string, options service.ScanOptions) (*nmap.Run, []string, error) {
+	s.mu.Lock()
+	s.options[target] = options
+	s.mu.Unlock()
+	return s.Scanner.Scan(ctx, target, options)
+}
+
+func (s *recordingScanner) scanOptions(target string) service.ScanOptions {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.options[target]
+}
+
+// lookupMode of the test harness, where vulnerabilities of found services are searched
+type lookupMode int
+
+const (
+	lookupScript     lookupMode = iota // by vulners script of nmap
+	lookupVulnersAPI                   // by the service in the vulners API mock
+	lookupNVD                          // by the service in NVD feeds from ./testdata/nvd
+)
+
 type VulnersControllerSuite struct {
 	suite.Suite
+	newScanner    func(vulnersAPIURL string) service.Scanner
+	lookup        lookupMode
+	tls           tlsMode             // transport security between the client and the server
+	authenticator *auth.Authenticator // if set, calls are authenticated and authorized by its interceptors
+
+	scanner    service.Scanner
+	recorder   *recordingScanner
+	resolver   *rebindingResolver
+	vulnersAPI *vulnersmock.Server
 
 	serverListener *bufconn.Listener
 	server         *grpc.Server
@@ -47,16 +144,77 @@ type VulnersControllerSuite struct {
 	Client         nmap_vulners_service.NetVulnServiceClient
 }
 
-func TestVulnersControllerSuite(t *testing.T) {
-	suite.Run(t, new(VulnersControllerSuite))
+// Runs the same tests without nmap, Docker and internet access, nmap output is replayed from recorded fixtures
+func TestVulnersControllerReplaySuite(t *testing.T) {
+	suite.Run(t, &VulnersControllerSuite{newScanner: func(string) service.Scanner {
+		return service.NewReplayScanner("./testdata/replay", 500*time.Millisecond)
+	}})
+}
+
+// Runs the same tests with vulnerabilities searched in Go, recorded vulners script output is replaced with the lookup results
+func TestVulnersControllerReplayLookupSuite(t *testing.T) {
+	suite.Run(t, &VulnersControllerSuite{
+		newScanner: func(string) service.Scanner {
+			return service.NewReplayScanner("./testdata/replay", 500*time.Millisecond)
+		},
+		lookup: lookupVulnersAPI,
+	})
+}
+
+// Runs the same tests over mutual TLS, as with grpc.tls.client_ca_file set
+func TestVulnersControllerReplayTLSSuite(t *testing.T) {
+	suite.Run(t, &VulnersControllerSuite{
+		newScanner: func(string) service.Scanner {
+			return service.NewReplayScanner("./testdata/replay", 500*time.Millisecond)
+		},
+		tls: tlsMutual,
+	})
 }
 
 func (s *VulnersControllerSuite) SetupSuite() {
+	serverOptions, clientCredentials := newTestTransport(s.T(), s.tls)
+	if s.authenticator != nil {
+		serverOptions = append(
+			serverOptions,
+			grpc.ChainUnaryInterceptor(s.authenticator.UnaryInterceptor()),
+			grpc.ChainStreamInterceptor(s.authenticator.StreamInterceptor()),
+		)
+	}
 	s.serverListener = bufconn.Listen(1024 * 1024)
-	s.server = grpc.NewServer()
+	s.server = grpc.NewServer(serverOptions...)
+
+	// vulners.com is replaced with a local stand-in, so results do not depend on its database updates
+	vulnersAPI, err := vulnersmock.New(slog.Default(), "../mock-vulners-api", mockVulnersAPIKey)
+	s.Require().NoError(err)
+	vulnersAPIURL, err := vulnersAPI.Start("127.0.0.1:0")
+	s.Require().NoError(err)
+	s.vulnersAPI = vulnersAPI
 
-	vulnersService := service.NewVulnersService(slog.Default(), 2*time.Minute, "../../scripts/vulners.nse")
-	grpccontroller.Register(s.server, vulnersService)
+	var resolver *lookup.Resolver
+	switch s.lookup {
+	case lookupVulnersAPI:
+		resolver = lookup.NewResolver(slog.Default(), lookup.NewVulnersAPI(vulnersAPIURL, mockVulnersAPIKey, 10*time.Second))
+	case lookupNVD:
+		nvd, err := lookup.NewNVD(slog.Default(), "./testdata/nvd")
+		s.Require().NoError(err)
+		resolver = lookup.NewResolver(slog.Default(), nvd)
+	}
+
+	kev, err := enrich.NewKEV(slog.Default(), "./testdata/kev/known_exploited_vulnerabilities.json")
+	s.Require().NoError(err)
+	epss, err := enrich.NewEPSS(slog.Default(), "./testdata/epss/epss_scores-2024-05-01.csv.gz")
+	s.Require().NoError(err)
+
+	s.scanner = s.newScanner(vulnersAPIURL)
+	s.recorder = &recordingScanner{Scanner: s.scanner, options: make(map[string]service.ScanOptions)}
+	vulnersService := service.NewVulnersService(slog.Default(), s.recorder, resolver, []service.Enricher{kev, epss}, 2*time.Minute, 4, false)
+	scansService := service.NewScansService(slog.Default(), vulnersService, memory.NewScanRepository(), 2, time.Hour)
+	// TEST-NET-1 stands for networks clients must not scan
+	scope := target.NewPolicy(target.Scope{Deny: []netip.Prefix{netip.MustParsePrefix("192.0.2.0/24")}}, nil)
+	portsParser, err := ports.NewParser(slog.Default(), "./testdata/nmap-services", []string{"top-1000"})
+	s.Require().NoError(err)
+	s.resolver = &rebindingResolver{}
+	grpccontroller.Register(s.server, scansService, target.NewParser(s.resolver, maxHosts), scope, portsParser)
 
 	go func() {
 		err := s.server.Serve(s.serverListener)
@@ -68,7 +226,7 @@ func (s *VulnersControllerSuite) SetupSuite() {
 		grpc.WithContextDialer(func(ctx context.Context, str string) (net.Conn, error) {
 			return s.serverListener.Dial()
 		}),
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithTransportCredentials(clientCredentials),
 	)
 	s.Require().NoError(err)
 	s.clientConn = conn
@@ -85,6 +243,46 @@ func (s *VulnersControllerSuite) TearDownSuite() {
 	if s.clientConn != nil {
 		s.clientConn.Close()
 	}
+	if s.vulnersAPI != nil {
+		s.vulnersAPI.Stop(context.Background())
+	}
+}
+
+// startHarness sets harness up as the server and the client for tests of another suite, tests of VulnersControllerSuite itself are not run
+func startHarness(t *testing.T, harness *VulnersControllerSuite) {
+	harness.SetT(t)
+	harness.SetupSuite()
+}
+
+// noVulns checks that services were scanned but nothing was found, e.g. ports are filtered
+func (s *VulnersControllerSuite) noVulns(services []*nmap_vulners_service.Service) {
+	for _, service := range services {
+		s.Empty(service.Vulns, "tcp port %d", service.TcpPort)
+		s.NotEqual("found", service.ScriptStatus, "tcp port %d", service.TcpPort)
+	}
+}
+
+// containsVuln checks that vulns has a vulnerability with the expected identifier
+// matching every non-empty field of expected.
+func (s *VulnersControllerSuite) containsVuln(vulns []*nmap_vulners_service.Vulnerability, expected *nmap_vulners_service.Vulnerability) {
+	for _, vuln := range vulns {
+		if vuln.Identifier != expected.Identifier {
+			continue
+		}
+		s.Equal(expected.CvssScore, vuln.CvssScore)
+		if expected.Type != "" {
+			s.Equal(expected.Type, vuln.Type)
+			s.Equal(fmt.Sprintf("https://vulners.com/%s/%s", expected.Type, expected.Identifier), vuln.Url)
+		}
+		if expected.Cpe != "" {
+			s.Equal(expected.Cpe, vuln.Cpe)
+		}
+		if expected.IsExploit {
+			s.True(vuln.IsExploit)
+		}
+		return
+	}
+	s.Fail(containsVulnMsg, expected.Identifier)
 }
 
 func (s *VulnersControllerSuite) TestCheckVuln_1() { // One target, one port
@@ -101,8 +299,14 @@ func (s *VulnersControllerSuite) TestCheckVuln_1() { // One target, one port
 	s.Equal("ssh", service.Name)
 	s.Equal("0.8.1", service.Version)
 	s.Equal(service.TcpPort, int32(11001))
+	s.Equal("tcp", service.Protocol)
+	s.Equal("open", service.State)
+	s.Equal("libssh", service.Product)
+	s.Equal("found", service.ScriptStatus)
+	s.Equal("up", res.Status)
+	s.Equal([]string{"localhost"}, res.Hostnames)
 	for _, vuln := range scanResultLocalhostPort11001 {
-		s.Contains(service.Vulns, vuln, containsVulnMsg)
+		s.containsVuln(service.Vulns, vuln)
 	}
 }
 
@@ -121,12 +325,12 @@ func (s *VulnersControllerSuite) TestCheckVuln_2() { // Multiple targets, one po
 	s.Equal("0.8.1", service.Version)
 	s.Equal(int32(11001), service.TcpPort)
 	for _, vuln := range scanResultLocalhostPort11001 {
-		s.Contains(service.Vulns, vuln, containsVulnMsg)
+		s.containsVuln(service.Vulns, vuln)
 	}
 
 	nikolab131Res := response.GetResults()[1]
 	s.Equal("178.140.10.168", nikolab131Res.Target)
-	s.Empty(nikolab131Res.Services)
+	s.noVulns(nikolab131Res.Services)
 }
 
 func (s *VulnersControllerSuite) TestCheckVuln_3() { // One target, multiple ports
@@ -144,16 +348,25 @@ func (s *VulnersControllerSuite) TestCheckVuln_3() { // One target, multiple por
 	s.Equal("ssh", firstService.Name)
 	s.Equal(int32(11001), firstService.TcpPort)
 	for _, vuln := range scanResultLocalhostPort11001 {
-		s.Contains(firstService.Vulns, vuln, containsVulnMsg)
+		s.containsVuln(firstService.Vulns, vuln)
 	}
 
 	secondService := res.Services[1]
 	s.Equal("http", secondService.Name)
 	s.Equal("1.13.2", secondService.Version)
 	s.Equal(int32(11002), secondService.TcpPort)
-	s.Contains(secondService.Vulns, &nmap_vulners_service.Vulnerability{Identifier: "NGINX:CVE-2017-7529", CvssScore: 5}, containsVulnMsg)
-	s.Contains(secondService.Vulns, &nmap_vulners_service.Vulnerability{Identifier: "SSV:96273", CvssScore: 5}, containsVulnMsg)
-	s.Contains(secondService.Vulns, &nmap_vulners_service.Vulnerability{Identifier: "PRION:CVE-2017-20005", CvssScore: 7.5}, containsVulnMsg)
+	s.containsVuln(secondService.Vulns, &nmap_vulners_service.Vulnerability{Identifier: "NGINX:CVE-2017-7529", CvssScore: 5, Type: "nginx"})
+	s.containsVuln(secondService.Vulns, &nmap_vulners_service.Vulnerability{Identifier: "SSV:96273", CvssScore: 5, Type: "seebug"})
+	s.containsVuln(secondService.Vulns, &nmap_vulners_service.Vulnerability{Identifier: "PRION:CVE-2017-20005", CvssScore: 7.5, Type: "prion"})
+
+	// vulners script checks both nginx CPEs, vulnerabilities from both of them are merged
+	s.Contains(secondService.Cpes, "cpe:/a:igor_sysoev:nginx:1.13.2")
+	s.Contains(secondService.Cpes, "cpe:/a:nginx:nginx:1.13.2")
+	identifiers := make(map[string]bool)
+	for _, vuln := range secondService.Vulns {
+		s.False(identifiers[vuln.Identifier], "duplicate vulnerability %s", vuln.Identifier)
+		identifiers[vuln.Identifier] = true
+	}
 }
 
 func (s *VulnersControllerSuite) TestCheckVuln_4() { // Multiple targets, multiple ports
@@ -171,18 +384,180 @@ func (s *VulnersControllerSuite) TestCheckVuln_4() { // Multiple targets, multip
 	s.Equal("0.8.1", firstService.Version)
 	s.Equal(int32(11001), firstService.TcpPort)
 	for _, vuln := range scanResultLocalhostPort11001 {
-		s.Contains(firstService.Vulns, vuln, containsVulnMsg)
+		s.containsVuln(firstService.Vulns, vuln)
 	}
 	secondService := localhostRes.Services[1]
 	s.Equal("http", secondService.Name)
 	s.Equal("1.13.2", secondService.Version)
 	s.Equal(int32(11002), secondService.TcpPort)
-	s.Contains(secondService.Vulns, &nmap_vulners_service.Vulnerability{Identifier: "NGINX:CVE-2017-7529", CvssScore: 5}, containsVulnMsg)
-	s.Contains(secondService.Vulns, &nmap_vulners_service.Vulnerability{Identifier: "SSV:96273", CvssScore: 5}, containsVulnMsg)
-	s.Contains(secondService.Vulns, &nmap_vulners_service.Vulnerability{Identifier: "PRION:CVE-2017-20005", CvssScore: 7.5}, containsVulnMsg)
+	s.containsVuln(secondService.Vulns, &nmap_vulners_service.Vulnerability{Identifier: "NGINX:CVE-2017-7529", CvssScore: 5, Type: "nginx"})
+	s.containsVuln(secondService.Vulns, &nmap_vulners_service.Vulnerability{Identifier: "SSV:96273", CvssScore: 5, Type: "seebug"})
+	s.containsVuln(secondService.Vulns, &nmap_vulners_service.Vulnerability{Identifier: "PRION:CVE-2017-20005", CvssScore: 7.5, Type: "prion"})
 
 	nikolab131Res := response.GetResults()[1]
-	s.Empty(nikolab131Res.Services)
+	s.Len(nikolab131Res.Services, 4, "ports without findings are reported too")
+	s.noVulns(nikolab131Res.Services)
+}
+
+func (s *VulnersControllerSuite) TestCheckVuln_UDP() {
+	if _, ok := s.scanner.(*service.ReplayScanner); !ok {
+		s.T().Skip("test environment has no UDP services")
+	}
+
+	ctx := context.Background()
+	response, err := s.Client.CheckVuln(ctx, &nmap_vulners_service.CheckVulnRequest{
+		Targets:  []string{"127.0.0.2"},
+		TcpPorts: []int32{11001},
+		UdpPorts: []int32{123, 161},
+	})
+	s.Require().NoError(err)
+
+	res := response.GetResults()[0]
+	s.Require().Len(res.Services, 3)
+	s.Equal("tcp", res.Services[0].Protocol)
+	s.Equal(int32(11001), res.Services[0].TcpPort)
+
+	ntp := res.Services[1]
+	s.Equal("udp", ntp.Protocol)
+	s.Equal("open|filtered", ntp.State)
+	s.Equal("skipped", ntp.ScriptStatus)
+
+	snmp := res.Services[2]
+	s.Equal("udp", snmp.Protocol)
+	s.Equal(int32(161), snmp.Port)
+	s.Equal(snmp.Port, snmp.TcpPort, "the deprecated field is still set")
+	s.Equal("net-snmp", snmp.Product)
+	s.Equal([]string{"CVE-2020-15862", "CVE-2018-18065"}, identifiers(snmp.Vulns))
+
+	// Only UDP ports are scanned if TCP ports are not given
+	response, err = s.Client.CheckVuln(ctx, &nmap_vulners_service.CheckVulnRequest{
+		Targets:  []string{"127.0.0.2"},
+		UdpPorts: []int32{161},
+	})
+	s.Require().NoError(err)
+	s.Require().Len(response.GetResults()[0].Services, 1)
+	s.Equal("udp", response.GetResults()[0].Services[0].Protocol)
+}
+
+func (s *VulnersControllerSuite) TestCheckVuln_OnlyKEV() {
+	ctx := context.Background()
+	response, err := s.Client.CheckVuln(ctx, &nmap_vulners_service.CheckVulnRequest{
+		Targets:  []string{"localhost"},
+		TcpPorts: []int32{11001, 11002},
+		OnlyKev:  true,
+	})
+	s.Require().NoError(err)
+
+	res := response.GetResults()[0]
+	s.Require().Len(res.Services, 2)
+	s.Require().Len(res.Services[0].Vulns, 1)
+	libsshVuln := res.Services[0].Vulns[0]
+	s.Equal("CVE-2018-10933", libsshVuln.Identifier)
+	s.True(libsshVuln.InKev)
+	s.Equal(time.Date(2022, 3, 25, 0, 0, 0, 0, time.UTC), libsshVuln.KevDateAdded.AsTime())
+
+	// CVE ID is found in vulners identifiers with a prefix too
+	s.Require().Len(res.Services[1].Vulns, 1)
+	s.Equal("NGINX:CVE-2017-7529", res.Services[1].Vulns[0].Identifier)
+	s.True(res.Services[1].Vulns[0].InKev)
+}
+
+func (s *VulnersControllerSuite) TestCheckVuln_EPSS() {
+	ctx := context.Background()
+	response, err := s.Client.CheckVuln(ctx, &nmap_vulners_service.CheckVulnRequest{
+		Targets:  []string{"localhost"},
+		TcpPorts: []int32{11001},
+		Sort:     nmap_vulners_service.VulnsSort_VULNS_SORT_EPSS,
+	})
+	s.Require().NoError(err)
+
+	vulns := response.GetResults()[0].Services[0].Vulns
+	s.Equal([]string{"CVE-2018-10933", "CVE-2019-14889", "CVE-2020-1730"}, identifiers(vulns))
+	s.InDelta(0.97065, vulns[0].Epss, 1e-6)
+	s.InDelta(0.99788, vulns[0].EpssPercentile, 1e-6)
+
+	response, err = s.Client.CheckVuln(ctx, &nmap_vulners_service.CheckVulnRequest{
+		Targets:  []string{"localhost"},
+		TcpPorts: []int32{11001},
+		MinEpss:  0.01,
+		Sort:     nmap_vulners_service.VulnsSort_VULNS_SORT_CVSS,
+	})
+	s.Require().NoError(err)
+	s.Equal([]string{"CVE-2019-14889", "CVE-2018-10933"}, identifiers(response.GetResults()[0].Services[0].Vulns))
+
+	_, err = s.Client.CheckVuln(ctx, &nmap_vulners_service.CheckVulnRequest{Targets: []string{"localhost"}, MinEpss: 2})
+	e, ok := status.FromError(err)
+	s.Require().True(ok)
+	s.Equal(codes.InvalidArgument, e.Code())
+}
+
+func (s *VulnersControllerSuite) TestCheckVuln_Filters() {
+	ctx := context.Background()
+	checkNginx := func(req *nmap_vulners_service.CheckVulnRequest) []string {
+		req.Targets = []string{"localhost"}
+		req.TcpPorts = []int32{11002}
+		response, err := s.Client.CheckVuln(ctx, req)
+		s.Require().NoError(err)
+		return identifiers(response.GetResults()[0].Services[0].Vulns)
+	}
+
+	s.Equal([]string{"PRION:CVE-2017-20005"}, checkNginx(&nmap_vulners_service.CheckVulnRequest{MinCvss: 6}))
+	s.Equal([]string{"SSV:96273"}, checkNginx(&nmap_vulners_service.CheckVulnRequest{OnlyExploits: true}))
+	s.ElementsMatch([]string{"NGINX:CVE-2017-7529"}, checkNginx(&nmap_vulners_service.CheckVulnRequest{IncludeTypes: []string{"NGINX"}}))
+	s.ElementsMatch([]string{"SSV:96273", "NGINX:CVE-2017-7529"}, checkNginx(&nmap_vulners_service.CheckVulnRequest{ExcludeTypes: []string{"prion"}}))
+	s.Equal([]string{"PRION:CVE-2017-20005"}, checkNginx(&nmap_vulners_service.CheckVulnRequest{
+		Sort:          nmap_vulners_service.VulnsSort_VULNS_SORT_CVSS,
+		MaxPerService: 1,
+	}))
+
+	// A service whose vulnerabilities are all filtered out is reported as having none
+	response, err := s.Client.CheckVuln(ctx, &nmap_vulners_service.CheckVulnRequest{Targets: []string{"localhost"}, TcpPorts: []int32{11002}, IncludeTypes: []string{"githubexploit"}})
+	s.Require().NoError(err)
+	nginx := response.GetResults()[0].Services[0]
+	s.Empty(nginx.Vulns)
+	s.Equal("not_found", nginx.ScriptStatus)
+
+	for _, req := range []*nmap_vulners_service.CheckVulnRequest{
+		{Targets: []string{"localhost"}, MinCvss: 11},
+		{Targets: []string{"localhost"}, MinCvss: float32(math.NaN())},
+		{Targets: []string{"localhost"}, MinEpss: float32(math.NaN())},
+		{Targets: []string{"localhost"}, MaxPerService: -1},
+	} {
+		_, err := s.Client.CheckVuln(ctx, req)
+		e, ok := status.FromError(err)
+		s.Require().True(ok)
+		s.Equal(codes.InvalidArgument, e.Code())
+	}
+}
+
+func (s *VulnersControllerSuite) TestCheckVulnStream() {
+	ctx := context.Background()
+	stream, err := s.Client.CheckVulnStream(ctx, &nmap_vulners_service.CheckVulnRequest{
+		Targets:  []string{"localhost", "nikolab131.xyz"},
+		TcpPorts: []int32{11001},
+	})
+	s.Require().NoError(err)
+
+	var results []*nmap_vulners_service.TargetsResult
+	var summary *nmap_vulners_service.ScanSummary
+	for {
+		response, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		s.Require().NoError(err)
+		s.Nil(summary, "summary must be the last message")
+		if result := response.GetResult(); result != nil {
+			results = append(results, result)
+		}
+		summary = response.GetSummary()
+	}
+
+	s.Len(results, 2)
+	s.Require().NotNil(summary)
+	s.Equal(int32(2), summary.HostsUp)
+	s.Equal(int32(2), summary.HostsTotal)
+	s.Positive(summary.Elapsed.AsDuration())
 }
 
 func (s *VulnersControllerSuite) TestCheckVuln_Empty() {
@@ -194,7 +569,7 @@ func (s *VulnersControllerSuite) TestCheckVuln_Empty() {
 	s.Require().NoError(err)
 
 	s.NotEmpty(response.GetResults()[0].Target)
-	s.Empty(response.GetResults()[0].Services)
+	s.noVulns(response.GetResults()[0].Services)
 }
 
 func (s *VulnersControllerSuite) TestCheckVuln_Validation() {
@@ -222,3 +597,267 @@ func (s *VulnersControllerSuite) TestCheckVuln_Validation() {
 		}
 	}
 }
+
+func (s *VulnersControllerSuite) TestCheckVuln_Targets() {
+	ctx := context.Background()
+	response, err := s.Client.CheckVuln(ctx, &nmap_vulners_service.CheckVulnRequest{
+		Targets:  []string{"localhost"},
+		TcpPorts: []int32{11001},
+	})
+	s.Require().NoError(err)
+	s.Require().Len(response.GetTargets(), 1)
+	resolved := response.GetTargets()[0]
+	s.Equal("localhost", resolved.Target)
+	s.Equal("hostname", resolved.Kind)
+	s.Equal(uint64(1), resolved.Hosts)
+	s.Contains(resolved.Addresses, "127.0.0.1")
+
+	for _, targets := range [][]string{{"localhost", "192.168.1.300"}, {"bad_host"}, {"10.0.0.0/16"}, {"10.0.0.0/24", "10.0.1.*"}} {
+		_, err = s.Client.CheckVuln(ctx, &nmap_vulners_service.CheckVulnRequest{Targets: targets, TcpPorts: []int32{22}})
+		s.Equal(codes.InvalidArgument, status.Code(err), targets)
+	}
+}
+
+func (s *VulnersControllerSuite) TestCheckVuln_Scope() {
+	ctx := context.Background()
+	_, err := s.Client.CheckVuln(ctx, &nmap_vulners_service.CheckVulnRequest{
+		Targets:  []string{"localhost", "192.0.2.10", "192.0.1-2.1"},
+		TcpPorts: []int32{22},
+	})
+	s.Equal(codes.PermissionDenied, status.Code(err))
+	s.Equal("targets are out of allowed scope: 192.0.2.10, 192.0.1-2.1", status.Convert(err).Message())
+
+	_, err = s.Client.StartScan(ctx, &nmap_vulners_service.CheckVulnRequest{Targets: []string{"192.0.2.0/28"}})
+	s.Equal(codes.PermissionDenied, status.Code(err))
+}
+
+func (s *VulnersControllerSuite) TestCheckVuln_DNSRebinding() {
+	if _, ok := s.scanner.(*service.ReplayScanner); !ok {
+		s.T().Skip("test environment has no services on 127.0.0.2")
+	}
+
+	ctx := context.Background()
+	response, err := s.Client.CheckVuln(ctx, &nmap_vulners_service.CheckVulnRequest{Targets: []string{rebindingHost}, TcpPorts: []int32{11001}})
+	s.Require().NoError(err)
+
+	// nmap gets the address checked against the scope, it has no chance to resolve the hostname again
+	s.Equal([]string{"127.0.0.2"}, s.recorder.scanOptions(rebindingHost).Addresses)
+	s.Equal(int32(1), s.resolver.lookups.Load())
+	res := response.GetResults()[0]
+	s.Equal("127.0.0.2", res.Target)
+	s.Equal([]string{rebindingHost}, res.Hostnames)
+	s.Equal([]string{"127.0.0.2"}, response.GetTargets()[0].Addresses)
+
+	// The hostname resolves to a denied address now
+	_, err = s.Client.CheckVuln(ctx, &nmap_vulners_service.CheckVulnRequest{Targets: []string{rebindingHost}, TcpPorts: []int32{11001}})
+	s.Equal(codes.PermissionDenied, status.Code(err))
+}
+
+func (s *VulnersControllerSuite) TestCheckVuln_Ports() {
+	ctx := context.Background()
+	response, err := s.Client.CheckVuln(ctx, &nmap_vulners_service.CheckVulnRequest{
+		Targets:       []string{"localhost"},
+		TcpPorts:      []int32{11002, 11001},
+		TcpPortRanges: []string{"11001-11002"},
+	})
+	s.Require().NoError(err)
+	s.Len(response.GetResults()[0].Services, 2, "duplicate ports are scanned once")
+
+	for _, req := range []*nmap_vulners_service.CheckVulnRequest{
+		{TcpPorts: []int32{-1}},
+		{TcpPorts: []int32{65536}},
+		{UdpPorts: []int32{0}},
+		{TcpPortRanges: []string{"11002-11001"}},
+		{UdpPortRanges: []string{"top-many"}},
+	} {
+		req.Targets = []string{"localhost"}
+		_, err = s.Client.CheckVuln(ctx, req)
+		s.Equal(codes.InvalidArgument, status.Code(err), req.String())
+	}
+
+	// Default ports are scanned when none are given
+	startResponse, err := s.Client.StartScan(ctx, &nmap_vulners_service.CheckVulnRequest{Targets: []string{"localhost"}})
+	s.Require().NoError(err)
+	getResponse, err := s.Client.GetScan(ctx, &nmap_vulners_service.GetScanRequest{ScanId: startResponse.ScanId})
+	s.Require().NoError(err)
+	s.Equal([]string{"1", "21-23", "25", "53", "80", "110", "139", "443", "445", "3389", "11001"}, getResponse.Scan.TcpPortRanges)
+	s.Equal([]int32{1, 25, 53, 80, 110, 139, 443, 445, 3389, 11001}, getResponse.Scan.TcpPorts)
+	_, err = s.Client.CancelScan(ctx, &nmap_vulners_service.CancelScanRequest{ScanId: startResponse.ScanId})
+	if err != nil {
+		s.Equal(codes.FailedPrecondition, status.Code(err), "the scan can finish before it is cancelled")
+	}
+}
+
+func (s *VulnersControllerSuite) TestStartScan() {
+	ctx := context.Background()
+	startResponse, err := s.Client.StartScan(ctx, &nmap_vulners_service.CheckVulnRequest{
+		Targets:  []string{"localhost"},
+		TcpPorts: []int32{11001},
+	})
+	s.Require().NoError(err)
+	s.NotEmpty(startResponse.GetScanId())
+
+	var scan *nmap_vulners_service.Scan
+	s.Eventually(func() bool {
+		response, err := s.Client.GetScan(ctx, &nmap_vulners_service.GetScanRequest{ScanId: startResponse.GetScanId()})
+		s.Require().NoError(err)
+		scan = response.GetScan()
+		return scan.GetStatus() == nmap_vulners_service.ScanStatus_SCAN_STATUS_DONE
+	}, 2*time.Minute, time.Second)
+
+	res := scan.GetResults()[0]
+	s.Equal("127.0.0.1", res.Target)
+	for _, vuln := range scanResultLocalhostPort11001 {
+		s.containsVuln(res.Services[0].Vulns, vuln)
+	}
+
+	_, err = s.Client.CancelScan(ctx, &nmap_vulners_service.CancelScanRequest{ScanId: startResponse.GetScanId()})
+	e, ok := status.FromError(err)
+	s.Require().True(ok)
+	s.Equal(codes.FailedPrecondition, e.Code())
+}
+
+func (s *VulnersControllerSuite) TestCancelScan() {
+	ctx := context.Background()
+	startResponse, err := s.Client.StartScan(ctx, &nmap_vulners_service.CheckVulnRequest{
+		Targets:  []string{"localhost"},
+		TcpPorts: []int32{11001, 11002},
+	})
+	s.Require().NoError(err)
+
+	response, err := s.Client.CancelScan(ctx, &nmap_vulners_service.CancelScanRequest{ScanId: startResponse.GetScanId()})
+	s.Require().NoError(err)
+	s.Equal(nmap_vulners_service.ScanStatus_SCAN_STATUS_CANCELLED, response.GetScan().GetStatus())
+
+	_, err = s.Client.GetScan(ctx, &nmap_vulners_service.GetScanRequest{ScanId: "unknown"})
+	e, ok := status.FromError(err)
+	s.Require().True(ok)
+	s.Equal(codes.NotFound, e.Code())
+}
+
+func (s *VulnersControllerSuite) TestListScans() {
+	ctx := context.Background()
+	_, err := s.Client.CheckVuln(ctx, &nmap_vulners_service.CheckVulnRequest{
+		Targets:  []string{"localhost"},
+		TcpPorts: []int32{11001},
+	})
+	s.Require().NoError(err)
+
+	response, err := s.Client.ListScans(ctx, &nmap_vulners_service.ListScansRequest{Target: "localhost", Limit: 1, IncludeResults: true})
+	s.Require().NoError(err)
+	s.Require().Len(response.GetScans(), 1)
+
+	scan := response.GetScans()[0]
+	s.Equal(nmap_vulners_service.ScanStatus_SCAN_STATUS_DONE, scan.GetStatus())
+	s.Equal([]int32{11001}, scan.GetTcpPorts())
+	s.Equal("127.0.0.1", scan.GetResults()[0].Target)
+
+	getResponse, err := s.Client.GetScan(ctx, &nmap_vulners_service.GetScanRequest{ScanId: scan.GetId()})
+	s.Require().NoError(err)
+	s.Equal(scan.GetId(), getResponse.GetScan().GetId())
+}
+
+// scanDone starts a scan and waits for it to finish
+func (s *VulnersControllerSuite) scanDone(req *nmap_vulners_service.CheckVulnRequest) string {
+	ctx := context.Background()
+	startResponse, err := s.Client.StartScan(ctx, req)
+	s.Require().NoError(err)
+
+	s.Eventually(func() bool {
+		response, err := s.Client.GetScan(ctx, &nmap_vulners_service.GetScanRequest{ScanId: startResponse.GetScanId()})
+		s.Require().NoError(err)
+		return response.GetScan().GetStatus() == nmap_vulners_service.ScanStatus_SCAN_STATUS_DONE
+	}, 2*time.Minute, time.Second)
+	return startResponse.GetScanId()
+}
+
+func (s *VulnersControllerSuite) TestDiffScans() {
+	ctx := context.Background()
+	scanIDs := make([]string, 2)
+	for i, ports := range [][]int32{{11001}, {11001, 11002}} {
+		scanIDs[i] = s.scanDone(&nmap_vulners_service.CheckVulnRequest{Targets: []string{"localhost"}, TcpPorts: ports})
+	}
+
+	response, err := s.Client.DiffScans(ctx, &nmap_vulners_service.DiffScansRequest{OldScanId: scanIDs[0], NewScanId: scanIDs[1]})
+	s.Require().NoError(err)
+	s.Require().Len(response.GetHosts(), 1)
+
+	host := response.GetHosts()[0]
+	s.Equal("127.0.0.1", host.Target)
+	s.Require().Len(host.OpenedPorts, 1)
+	s.Equal(int32(11002), host.OpenedPorts[0].TcpPort)
+	s.Empty(host.ClosedPorts)
+	s.Empty(host.ChangedServices)
+
+	response, err = s.Client.DiffScans(ctx, &nmap_vulners_service.DiffScansRequest{OldScanId: scanIDs[0], NewScanId: scanIDs[0]})
+	s.Require().NoError(err)
+	s.Empty(response.GetHosts())
+}
+
+func (s *VulnersControllerSuite) TestDiffScans_Protocols() {
+	if _, ok := s.scanner.(*service.ReplayScanner); !ok {
+		s.T().Skip("test environment has no UDP services")
+	}
+
+	// 127.0.0.3 has DNS on both 53/tcp and 53/udp
+	ctx := context.Background()
+	bothID := s.scanDone(&nmap_vulners_service.CheckVulnRequest{Targets: []string{"127.0.0.3"}, TcpPorts: []int32{53}, UdpPorts: []int32{53}})
+	tcpID := s.scanDone(&nmap_vulners_service.CheckVulnRequest{Targets: []string{"127.0.0.3"}, TcpPorts: []int32{53}})
+	udpID := s.scanDone(&nmap_vulners_service.CheckVulnRequest{Targets: []string{"127.0.0.3"}, UdpPorts: []int32{53}})
+
+	response, err := s.Client.DiffScans(ctx, &nmap_vulners_service.DiffScansRequest{OldScanId: bothID, NewScanId: udpID})
+	s.Require().NoError(err)
+	s.Require().Len(response.GetHosts(), 1)
+	host := response.GetHosts()[0]
+	s.Empty(host.OpenedPorts)
+	s.Require().Len(host.ClosedPorts, 1, "53/udp must not hide that 53/tcp is closed")
+	s.Equal("tcp", host.ClosedPorts[0].Protocol)
+	s.Equal(int32(53), host.ClosedPorts[0].Port)
+
+	response, err = s.Client.DiffScans(ctx, &nmap_vulners_service.DiffScansRequest{OldScanId: tcpID, NewScanId: udpID})
+	s.Require().NoError(err)
+	s.Require().Len(response.GetHosts(), 1)
+	host = response.GetHosts()[0]
+	s.Require().Len(host.OpenedPorts, 1, "a service moved to another protocol")
+	s.Equal("udp", host.OpenedPorts[0].Protocol)
+	s.Require().Len(host.ClosedPorts, 1)
+	s.Equal("tcp", host.ClosedPorts[0].Protocol)
+	s.Empty(host.ChangedServices)
+
+	response, err = s.Client.DiffScans(ctx, &nmap_vulners_service.DiffScansRequest{OldScanId: tcpID, NewScanId: bothID})
+	s.Require().NoError(err)
+	s.Require().Len(response.GetHosts(), 1)
+	host = response.GetHosts()[0]
+	s.Require().Len(host.OpenedPorts, 1)
+	s.Equal("udp", host.OpenedPorts[0].Protocol)
+	s.Empty(host.ClosedPorts)
+}
+
+func (s *VulnersControllerSuite) TestImportNmapXML() {
+	ctx := context.Background()
+	xml, err := os.ReadFile("./testdata/replay/localhost.xml")
+	s.Require().NoError(err)
+
+	response, err := s.Client.ImportNmapXML(ctx, &nmap_vulners_service.ImportNmapXMLRequest{Xml: xml})
+	s.Require().NoError(err)
+
+	scan := response.GetScan()
+	s.Equal(nmap_vulners_service.ScanStatus_SCAN_STATUS_DONE, scan.GetStatus())
+	s.Equal(int32(1), scan.GetSummary().GetHostsUp())
+	res := scan.GetResults()[0]
+	s.Equal("127.0.0.1", res.Target)
+	s.Equal(int32(11001), res.Services[0].TcpPort)
+	for _, vuln := range scanResultLocalhostPort11001 {
+		s.containsVuln(res.Services[0].Vulns, vuln)
+	}
+	s.containsVuln(res.Services[1].Vulns, &nmap_vulners_service.Vulnerability{Identifier: "SSV:96273", CvssScore: 5, Type: "seebug", IsExploit: true})
+
+	getResponse, err := s.Client.GetScan(ctx, &nmap_vulners_service.GetScanRequest{ScanId: scan.GetId()})
+	s.Require().NoError(err)
+	s.Equal(scan.GetId(), getResponse.GetScan().GetId())
+
+	_, err = s.Client.ImportNmapXML(ctx, &nmap_vulners_service.ImportNmapXMLRequest{Xml: []byte("not xml")})
+	e, ok := status.FromError(err)
+	s.Require().True(ok)
+	s.Equal(codes.InvalidArgument, e.Code())
+}